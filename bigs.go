@@ -0,0 +1,177 @@
+package gochart
+
+import (
+	"math/big"
+)
+
+// BigInts is a sequence of arbitrary precision integer X values.
+// BigInts works like Ints but its values are *big.Int.
+// Note that BigInts implements the Values interface.
+type BigInts struct {
+	start *big.Int
+	inc   *big.Int
+	count int
+}
+
+// NewBigInts returns a sequence of count integers starting at start and
+// incrementing by inc.
+func NewBigInts(start, inc *big.Int, count int) *BigInts {
+	return &BigInts{
+		start: new(big.Int).Set(start),
+		inc:   new(big.Int).Set(inc),
+		count: count,
+	}
+}
+
+// Apply applies f to each of these X values and returns the resulting
+// Y values. f must not modify x.
+func (b *BigInts) Apply(f func(x *big.Int) int64) Values {
+	result := make(valueSlice, b.count)
+	for i := 0; i < b.count; i++ {
+		result[i] = f(b.value(i))
+	}
+	return result
+}
+
+// ApplyBigInt applies f to each of these X values and returns the
+// resulting Y values. f must store the result in result and return result.
+// f must not modify x.
+func (b *BigInts) ApplyBigInt(
+	f func(x *big.Int, result *big.Int) *big.Int) Values {
+	result := make(valueSlice, b.count)
+	for i := 0; i < b.count; i++ {
+		result[i] = f(b.value(i), new(big.Int))
+	}
+	return result
+}
+
+func (b *BigInts) Value(idx int) interface{} {
+	if idx < 0 || idx >= b.count {
+		panic(kIdxOutOfRange)
+	}
+	return b.value(idx)
+}
+
+func (b *BigInts) Len() int {
+	return b.count
+}
+
+func (b *BigInts) value(idx int) *big.Int {
+	result := big.NewInt(int64(idx))
+	result.Mul(result, b.inc)
+	return result.Add(result, b.start)
+}
+
+// BigFloats is a sequence of arbitrary precision floating point X values.
+// BigFloats works like Floats but its values are *big.Float.
+// Note that BigFloats implements the Values interface.
+type BigFloats struct {
+	start *big.Float
+	inc   *big.Float
+	count int
+	prec  uint
+}
+
+// NewBigFloats returns a sequence of count floats starting at start and
+// incrementing by inc. The precision of the floats is the greater of the
+// precisions of start and inc.
+func NewBigFloats(start, inc *big.Float, count int) *BigFloats {
+	prec := start.Prec()
+	if inc.Prec() > prec {
+		prec = inc.Prec()
+	}
+	return &BigFloats{
+		start: new(big.Float).SetPrec(prec).Set(start),
+		inc:   new(big.Float).SetPrec(prec).Set(inc),
+		count: count,
+		prec:  prec,
+	}
+}
+
+// Apply applies fn to each of these X values and returns the resulting
+// Y values. fn must store the result in result and return result. result
+// comes with the same precision as these X values. fn must not modify x.
+func (b *BigFloats) Apply(
+	fn func(x *big.Float, result *big.Float) *big.Float) Values {
+	result := make(valueSlice, b.count)
+	for i := 0; i < b.count; i++ {
+		result[i] = fn(b.value(i), b.newFloat())
+	}
+	return result
+}
+
+// ApplyInv applies the inverse of fn to each of these X values and returns
+// the resulting Y values. fn works like it does in Apply. The Y values that
+// ApplyInv produces will be between lower and upper. fn must be monotone
+// increasing or decreasing between lower and upper. ApplyInv finds each
+// Y value by bisection to the full precision of these X values.
+func (b *BigFloats) ApplyInv(
+	fn func(x *big.Float, result *big.Float) *big.Float,
+	lower, upper *big.Float) Values {
+	result := make(valueSlice, b.count)
+	for i := 0; i < b.count; i++ {
+		result[i] = b.inverse(fn, b.value(i), lower, upper)
+	}
+	return result
+}
+
+func (b *BigFloats) Value(idx int) interface{} {
+	if idx < 0 || idx >= b.count {
+		panic(kIdxOutOfRange)
+	}
+	return b.value(idx)
+}
+
+func (b *BigFloats) Len() int {
+	return b.count
+}
+
+func (b *BigFloats) value(idx int) *big.Float {
+	result := b.newFloat().SetInt64(int64(idx))
+	result.Mul(result, b.inc)
+	return result.Add(result, b.start)
+}
+
+func (b *BigFloats) newFloat() *big.Float {
+	return new(big.Float).SetPrec(b.prec)
+}
+
+// inverse returns x between lower and upper such that fn(x) = y.
+func (b *BigFloats) inverse(
+	fn func(x *big.Float, result *big.Float) *big.Float,
+	y, lower, upper *big.Float) *big.Float {
+	lo := b.newFloat().Set(lower)
+	hi := b.newFloat().Set(upper)
+	fLo := fn(lo, b.newFloat())
+	fHi := fn(hi, b.newFloat())
+	if fLo.Cmp(y) == 0 {
+		return lo
+	}
+	if fHi.Cmp(y) == 0 {
+		return hi
+	}
+	decreasing := fLo.Cmp(fHi) > 0
+	half := big.NewFloat(0.5)
+	fx := b.newFloat()
+
+	// When the answer is near 0, one bound can halve through the whole
+	// exponent range without meeting the other, so cap the iterations.
+	mid := b.newFloat()
+	for i := uint(0); i < b.prec+64; i++ {
+		mid = b.newFloat().Add(lo, hi)
+		mid.Mul(mid, half)
+		if mid.Cmp(lo) == 0 || mid.Cmp(hi) == 0 {
+			return mid
+		}
+		cmp := fn(mid, fx).Cmp(y)
+		if cmp == 0 {
+			return mid
+		}
+		if (cmp > 0) != decreasing {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return mid
+}
@@ -0,0 +1,187 @@
+package gochart
+
+import (
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache memoizes the results of expensive functions so that charts of
+// overlapping ranges can reuse prior computations. A Cache identifies
+// each function by a caller chosen name, so callers must give different
+// functions different names and must change a function's name whenever
+// they change what it computes. A Cache can be saved to and loaded from
+// a file. A Cache is safe to use from multiple goroutines.
+type Cache struct {
+	mu   sync.Mutex
+	data cacheData
+}
+
+// NewCache returns a new, empty Cache.
+func NewCache() *Cache {
+	return &Cache{data: newCacheData()}
+}
+
+// LoadCache reads a Cache from the file at path that Cache.SaveFile
+// wrote. If there is no file at path, LoadCache returns a new, empty
+// Cache.
+func LoadCache(path string) (*Cache, error) {
+	result := NewCache()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := result.Load(f); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Int returns a memoized version of f, which is named name, suitable for
+// Ints.Apply.
+func (c *Cache) Int(name string, f func(int64) int64) func(int64) int64 {
+	return func(x int64) int64 {
+		key := cacheKey{Name: name, X: x}
+		c.mu.Lock()
+		result, ok := c.data.Ints[key]
+		c.mu.Unlock()
+		if ok {
+			return result
+		}
+		result = f(x)
+		c.mu.Lock()
+		c.data.Ints[key] = result
+		c.mu.Unlock()
+		return result
+	}
+}
+
+// Float returns a memoized version of f, which is named name, suitable
+// for Floats.Apply.
+func (c *Cache) Float(
+	name string, f func(float64) float64) func(float64) float64 {
+	return func(x float64) float64 {
+		key := floatCacheKey{Name: name, X: x}
+		c.mu.Lock()
+		result, ok := c.data.Floats[key]
+		c.mu.Unlock()
+		if ok {
+			return result
+		}
+		result = f(x)
+		c.mu.Lock()
+		c.data.Floats[key] = result
+		c.mu.Unlock()
+		return result
+	}
+}
+
+// BigInt returns a memoized version of f, which is named name, suitable
+// for Ints.ApplyBigInt.
+func (c *Cache) BigInt(
+	name string,
+	f func(x int64, result *big.Int) *big.Int,
+) func(x int64, result *big.Int) *big.Int {
+	return func(x int64, result *big.Int) *big.Int {
+		key := cacheKey{Name: name, X: x}
+		c.mu.Lock()
+		cached, ok := c.data.BigInts[key]
+		c.mu.Unlock()
+		if ok {
+			return result.Set(cached)
+		}
+		f(x, result)
+		c.mu.Lock()
+		c.data.BigInts[key] = new(big.Int).Set(result)
+		c.mu.Unlock()
+		return result
+	}
+}
+
+// Len returns the number of results stored in this Cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.data.Ints) + len(c.data.Floats) + len(c.data.BigInts)
+}
+
+// Save writes the contents of this Cache to w in gob format.
+func (c *Cache) Save(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return gob.NewEncoder(w).Encode(&c.data)
+}
+
+// Load reads results that Save wrote from r and adds them to this Cache.
+func (c *Cache) Load(r io.Reader) error {
+	data := newCacheData()
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range data.Ints {
+		c.data.Ints[k] = v
+	}
+	for k, v := range data.Floats {
+		c.data.Floats[k] = v
+	}
+	for k, v := range data.BigInts {
+		c.data.BigInts[k] = v
+	}
+	return nil
+}
+
+// SaveFile saves the contents of this Cache to the file at path. SaveFile
+// writes to a temporary file first so that the file at path is never left
+// partially written.
+func (c *Cache) SaveFile(path string) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if err := c.Save(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+type cacheKey struct {
+	Name string
+	X    int64
+}
+
+type floatCacheKey struct {
+	Name string
+	X    float64
+}
+
+// cacheData is the contents of a Cache. Its fields are exported so that
+// it works with encoding/gob.
+type cacheData struct {
+	Ints    map[cacheKey]int64
+	Floats  map[floatCacheKey]float64
+	BigInts map[cacheKey]*big.Int
+}
+
+func newCacheData() cacheData {
+	return cacheData{
+		Ints:    make(map[cacheKey]int64),
+		Floats:  make(map[floatCacheKey]float64),
+		BigInts: make(map[cacheKey]*big.Int),
+	}
+}
@@ -0,0 +1,99 @@
+package gochart_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestCache(t *testing.T) {
+	cache := gochart.NewCache()
+	calls := 0
+	square := cache.Int("square", func(x int64) int64 {
+		calls++
+		return x * x
+	})
+	ys := gochart.NewInts(1, 1, 5).Apply(square)
+	assertValuesEqual(
+		t, ys, int64(1), int64(4), int64(9), int64(16), int64(25))
+	assertEqual(t, 5, calls)
+	ys = gochart.NewInts(4, 1, 3).Apply(square)
+	assertValuesEqual(t, ys, int64(16), int64(25), int64(36))
+	assertEqual(t, 6, calls)
+	cube := cache.Int("cube", func(x int64) int64 { return x * x * x })
+	assertEqual(t, int64(8), cube(2))
+	assertEqual(t, 7, cache.Len())
+}
+
+func TestCacheSaveLoad(t *testing.T) {
+	cache := gochart.NewCache()
+	factorial := func(x int64, result *big.Int) *big.Int {
+		return result.MulRange(1, x)
+	}
+	gochart.NewInts(20, 5, 3).ApplyBigInt(cache.BigInt("fact", factorial))
+	gochart.NewFloats(1.0, 1.0, 2).Apply(cache.Float("sqrt", math.Sqrt))
+	gochart.NewInts(1, 1, 2).Apply(
+		cache.Int("neg", func(x int64) int64 { return -x }))
+	var buffer bytes.Buffer
+	if err := cache.Save(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	loaded := gochart.NewCache()
+	if err := loaded.Load(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 7, loaded.Len())
+	ys := gochart.NewInts(20, 5, 3).ApplyBigInt(
+		loaded.BigInt("fact", func(x int64, result *big.Int) *big.Int {
+			t.Error("Expected cached value")
+			return result
+		}))
+	assertSprintValuesEqual(
+		t, ys,
+		"2432902008176640000",
+		"15511210043330985984000000",
+		"265252859812191058636308480000000")
+	sqrt := loaded.Float("sqrt", func(x float64) float64 {
+		t.Error("Expected cached value")
+		return 0.0
+	})
+	assertEqual(t, math.Sqrt2, sqrt(2.0))
+	neg := loaded.Int("neg", func(x int64) int64 { return 0 })
+	assertEqual(t, int64(-2), neg(2))
+}
+
+func TestCacheFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gochart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.gob")
+	cache, err := gochart.LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 0, cache.Len())
+	gochart.NewInts(1, 1, 10).Apply(
+		cache.Int("double", func(x int64) int64 { return 2 * x }))
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	cache, err = gochart.LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 10, cache.Len())
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gochart.LoadCache(path); err == nil {
+		t.Error("Expected error loading garbage")
+	}
+}
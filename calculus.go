@@ -0,0 +1,76 @@
+package gochart
+
+// ApplyDerivative returns the derivative of fn at each of these X values
+// estimated with the central difference (fn(x+h) - fn(x-h)) / 2h. The
+// error is proportional to h squared.
+func (f *Floats) ApplyDerivative(
+	fn func(float64) float64, h float64) Values {
+	result := make(valueSlice, f.count)
+	for i := 0; i < f.count; i++ {
+		result[i] = centralDifference(fn, f.value(i), h)
+	}
+	return result
+}
+
+// ApplyRichardson works like ApplyDerivative but improves each central
+// difference with Richardson extrapolation, combining the estimates for h
+// and h/2 so that the error is proportional to h to the fourth power.
+func (f *Floats) ApplyRichardson(
+	fn func(float64) float64, h float64) Values {
+	result := make(valueSlice, f.count)
+	for i := 0; i < f.count; i++ {
+		x := f.value(i)
+		d1 := centralDifference(fn, x, h)
+		d2 := centralDifference(fn, x, h/2.0)
+		result[i] = (4.0*d2 - d1) / 3.0
+	}
+	return result
+}
+
+// ApplySimpson returns the integral of fn from the first of these X values
+// to each X value. The first Y value is always 0. ApplySimpson applies
+// Simpson's rule between consecutive X values, evaluating fn at each X
+// value and at the midpoints between them, so it is exact for cubic
+// polynomials.
+func (f *Floats) ApplySimpson(fn func(float64) float64) Values {
+	return f.cumulativeIntegral(func(a, b, fa, fb float64) float64 {
+		return (b - a) / 6.0 * (fa + 4.0*fn((a+b)/2.0) + fb)
+	}, fn)
+}
+
+// ApplyTrapezoid returns the integral of fn from the first of these X
+// values to each X value like ApplySimpson does, but it applies the
+// trapezoid rule, evaluating fn only at these X values.
+func (f *Floats) ApplyTrapezoid(fn func(float64) float64) Values {
+	return f.cumulativeIntegral(func(a, b, fa, fb float64) float64 {
+		return (b - a) / 2.0 * (fa + fb)
+	}, fn)
+}
+
+// cumulativeIntegral returns the running sum of the integrals of fn
+// between consecutive X values. rule estimates the integral of fn from a
+// to b given fa = fn(a) and fb = fn(b).
+func (f *Floats) cumulativeIntegral(
+	rule func(a, b, fa, fb float64) float64,
+	fn func(float64) float64) Values {
+	result := make(valueSlice, f.count)
+	if f.count == 0 {
+		return result
+	}
+	a := f.value(0)
+	fa := fn(a)
+	sum := 0.0
+	result[0] = sum
+	for i := 1; i < f.count; i++ {
+		b := f.value(i)
+		fb := fn(b)
+		sum += rule(a, b, fa, fb)
+		result[i] = sum
+		a, fa = b, fb
+	}
+	return result
+}
+
+func centralDifference(fn func(float64) float64, x, h float64) float64 {
+	return (fn(x+h) - fn(x-h)) / (2.0 * h)
+}
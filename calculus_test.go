@@ -0,0 +1,45 @@
+package gochart_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestApplyDerivative(t *testing.T) {
+	xs := gochart.NewFloats(0.5, 0.5, 4)
+	ys := xs.ApplyDerivative(math.Sin, 1e-4)
+	for i := 0; i < xs.Len(); i++ {
+		assertCloseTo(t, math.Cos(xs.Value(i).(float64)), ys.Value(i).(float64))
+	}
+}
+
+func TestApplyRichardson(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 3)
+	cube := func(x float64) float64 { return x * x * x }
+	ys := xs.ApplyRichardson(cube, 0.5)
+	assertCloseTo(t, 3.0, ys.Value(0).(float64))
+	assertCloseTo(t, 12.0, ys.Value(1).(float64))
+	assertCloseTo(t, 27.0, ys.Value(2).(float64))
+	plain := xs.ApplyDerivative(math.Exp, 0.1).Value(0).(float64)
+	better := xs.ApplyRichardson(math.Exp, 0.1).Value(0).(float64)
+	if math.Abs(better-math.E) >= math.Abs(plain-math.E) {
+		t.Errorf("Expected Richardson to beat plain: %v %v", better, plain)
+	}
+}
+
+func TestApplySimpson(t *testing.T) {
+	xs := gochart.NewFloats(0.0, 1.0, 4)
+	ys := xs.ApplySimpson(func(x float64) float64 { return x * x * x })
+	assertValuesEqual(t, ys, 0.0, 0.25, 4.0, 20.25)
+	ys = gochart.NewFloats(0.0, math.Pi/20, 21).ApplySimpson(math.Sin)
+	assertCloseTo(t, 2.0, ys.Value(20).(float64))
+	assertEqual(t, 0, gochart.NewFloats(0, 1, 0).ApplySimpson(math.Sin).Len())
+}
+
+func TestApplyTrapezoid(t *testing.T) {
+	xs := gochart.NewFloats(0.0, 1.0, 3)
+	ys := xs.ApplyTrapezoid(func(x float64) float64 { return x * x })
+	assertValuesEqual(t, ys, 0.0, 0.5, 3.0)
+}
@@ -3,10 +3,12 @@ package gochart
 import (
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"os"
-	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/keep94/gomath"
 )
@@ -21,6 +23,9 @@ type Ints struct {
 	start int64
 	inc   int64
 	count int
+
+	// If true, inc is the ratio between consecutive values.
+	geometric bool
 }
 
 // NewInts returns a sequence of count integers starting at start and
@@ -29,6 +34,13 @@ func NewInts(start, inc int64, count int) *Ints {
 	return &Ints{start: start, inc: inc, count: count}
 }
 
+// NewGeometricInts returns a sequence of count integers starting at start
+// where each integer is ratio times the previous one. For instance,
+// NewGeometricInts(1, 2, 5) gives 1, 2, 4, 8, 16.
+func NewGeometricInts(start, ratio int64, count int) *Ints {
+	return &Ints{start: start, inc: ratio, count: count, geometric: true}
+}
+
 // Apply applies f to each of these X values and returns the resulting
 // Y values.
 func (i *Ints) Apply(f func(int64) int64) Values {
@@ -100,6 +112,9 @@ func (i *Ints) Len() int {
 }
 
 func (i *Ints) value(idx int) int64 {
+	if i.geometric {
+		return i.start * powInt(i.inc, idx)
+	}
 	return i.start + int64(idx)*i.inc
 }
 
@@ -108,7 +123,9 @@ func (i *Ints) value(idx int) int64 {
 type Floats struct {
 	start float64
 	inc   float64
+	end   float64
 	count int
+	scale scaleType
 }
 
 // NewFloats returns a sequence of count floats starting at start and
@@ -117,6 +134,26 @@ func NewFloats(start, inc float64, count int) *Floats {
 	return &Floats{start: start, inc: inc, count: count}
 }
 
+// NewGeometricFloats returns a sequence of count floats starting at start
+// where each float is ratio times the previous one.
+func NewGeometricFloats(start, ratio float64, count int) *Floats {
+	return &Floats{start: start, inc: ratio, count: count, scale: kGeometric}
+}
+
+// NewLogFloats returns a sequence of count floats from start to end that
+// are evenly spaced on a logarithmic scale. start and end must be positive.
+// For instance, NewLogFloats(2, 2000, 4) gives 2, 20, 200, 2000. The first
+// float is exactly start, and if count > 1, the last float is exactly end.
+func NewLogFloats(start, end float64, count int) *Floats {
+	var logRatio float64
+	if count > 1 {
+		logRatio = math.Log10(end/start) / float64(count-1)
+	}
+	return &Floats{
+		start: start, inc: logRatio, end: end, count: count,
+		scale: kLogarithmic}
+}
+
 // Apply applies fn to each of these X values and returns the resulting
 // Y values.
 func (f *Floats) Apply(fn func(float64) float64) Values {
@@ -130,7 +167,8 @@ func (f *Floats) Apply(fn func(float64) float64) Values {
 // ApplyInv applies the inverse of fn to each of these X values and returns the
 // resulting Y values. The Y values that ApplyInv produces will be between
 // lower and upper. fn must be monotone increasing or decreasing between
-// lower and upper.
+// lower and upper. X values that fn cannot reach between lower and upper
+// give lower or upper; use ApplyInverse to detect them instead.
 func (f *Floats) ApplyInv(
 	fn func(float64) float64, lower, upper float64) Values {
 	result := make(valueSlice, f.count)
@@ -152,9 +190,39 @@ func (f *Floats) Len() int {
 }
 
 func (f *Floats) value(idx int) float64 {
-	return f.start + float64(idx)*f.inc
+	switch f.scale {
+	case kGeometric:
+		return f.start * math.Pow(f.inc, float64(idx))
+	case kLogarithmic:
+		if idx == 0 {
+			return f.start
+		}
+		if idx == f.count-1 {
+			return f.end
+		}
+
+		// Scaling start rather than adding to its logarithm keeps values
+		// such as 2, 20, 200 exact.
+		return f.start * math.Pow(10, float64(idx)*f.inc)
+	default:
+		return f.start + float64(idx)*f.inc
+	}
 }
 
+// scaleType tells how the values of a Floats instance are spaced.
+type scaleType int
+
+const (
+	// start, start+inc, start+2*inc, ...
+	kLinear scaleType = iota
+
+	// start, start*inc, start*inc^2, ...
+	kGeometric
+
+	// start, start*10^inc, start*10^(2*inc), ..., end
+	kLogarithmic
+)
+
 // Interface Values represents a sequence values for either the X or Y column
 // of a chart.
 type Values interface {
@@ -223,13 +291,59 @@ func NumCols(count int) Option {
 	})
 }
 
+// WrapWidth sets the maximum width of a cell. Values wider than width are
+// split across several lines within the same row so that the X value and
+// borders stay aligned. The last line of a wrapped value holds its
+// rightmost characters so that wrapped numbers line up with unwrapped ones.
+// The default of 0 means values are never wrapped.
+func WrapWidth(width int) Option {
+	return optionFunc(func(s *settingsType) {
+		s.wrapWidth = width
+	})
+}
+
+// RuleEvery draws a horizontal rule, in the same style as the top and
+// bottom borders, after every n rows of the chart.
+func RuleEvery(n int) Option {
+	return optionFunc(func(s *settingsType) {
+		s.ruleEvery = n
+	})
+}
+
+// RulesAt draws a horizontal rule just before each row that contains one
+// of the given X values. X values match when they format the same way
+// according to XFormat, so RulesAt(100) matches int64(100).
+func RulesAt(xs ...interface{}) Option {
+	return optionFunc(func(s *settingsType) {
+		s.rulesAt = append(s.rulesAt, xs...)
+	})
+}
+
+// Column adds a column of values to the right of the Y values, formatted
+// with fmtStr. Each Column option adds one more column. vs must have the
+// same length as the X values or else NewChart panics.
+func Column(vs Values, fmtStr string) Option {
+	return optionFunc(func(s *settingsType) {
+		s.columns = append(s.columns, columnType{values: vs, format: fmtStr})
+	})
+}
+
 // Chart represents a chart of X and Y values.
 type Chart struct {
-	header   string
-	xyFormat string
-	numRows  int
-	numCols  int
-	xyValues xyValuesType
+	xs             Values
+	ys             Values
+	settings       *settingsType
+	header         string
+	widths         []int
+	numRows        int
+	numCols        int
+	wrapWidth      int
+	rules          []bool
+	xyValues       xyValuesType
+	footer         xyValuesType
+	transposeWidth int
+	styled         bool
+	alwaysStyle    bool
 }
 
 // NewChart creates a new chart. xs are the X values and ys are the Y values.
@@ -241,24 +355,74 @@ func NewChart(xs, ys Values, options ...Option) *Chart {
 	}
 	settings := &settingsType{xFormat: "%v", yFormat: "%v"}
 	Options(options).mutate(settings)
-	settings.computeDimensions(xs.Len())
-	xyValues := createXYValues(xs, ys, settings.xFormat, settings.yFormat)
-	xwidth, ywidth := xyValues.widths()
-	return &Chart{
-		header:   createHeader(xwidth, ywidth, settings.numCols),
-		xyFormat: createXYFormat(xwidth, ywidth),
-		numRows:  settings.numRows,
-		numCols:  settings.numCols,
-		xyValues: xyValues}
+	for _, column := range settings.columns {
+		if column.values.Len() != xs.Len() {
+			panic("columns must have same length as xs")
+		}
+	}
+	return newChart(xs, ys, settings)
 }
 
-func createHeader(xwidth, ywidth, numCols int) string {
-	piece := "+" + strings.Repeat("-", xwidth) + "+" + strings.Repeat("-", ywidth)
-	return fmt.Sprintf("%s+", strings.Repeat(piece, numCols))
+// newChart creates a new chart from xs, ys, and settings. xs, ys, and
+// the columns in settings must have the same length.
+func newChart(xs, ys Values, settings *settingsType) *Chart {
+	settings.computeDimensions(xs.Len())
+	xyValues := createXYValues(xs, ys, settings)
+	footer := createFooter(ys, settings)
+	widths := xyValues.widths(settings.numYs(), settings.wrapWidth)
+	for i, width := range footer.widths(1, settings.wrapWidth) {
+		widths[i] = maxInt(widths[i], width)
+	}
+	return &Chart{
+		xs:             xs,
+		ys:             ys,
+		settings:       settings,
+		header:         createHeader(widths, settings.numCols),
+		widths:         widths,
+		numRows:        settings.numRows,
+		numCols:        settings.numCols,
+		wrapWidth:      settings.wrapWidth,
+		rules:          createRules(xyValues, settings),
+		xyValues:       xyValues,
+		footer:         footer,
+		transposeWidth: settings.transposeWidth,
+		styled:         len(settings.highlights) > 0 || settings.styles != nil,
+		alwaysStyle:    settings.alwaysStyle}
+}
+
+// createRules returns which rows of the chart get a horizontal rule drawn
+// just before them.
+func createRules(xyValues xyValuesType, s *settingsType) []bool {
+	result := make([]bool, s.numRows)
+	if s.ruleEvery > 0 {
+		for i := s.ruleEvery; i < s.numRows; i += s.ruleEvery {
+			result[i] = true
+		}
+	}
+	if len(s.rulesAt) > 0 {
+		ruleXs := make(map[string]bool, len(s.rulesAt))
+		for _, x := range s.rulesAt {
+			ruleXs[s.format(s.xFormat, x)] = true
+		}
+		for i := range xyValues {
+			if ruleXs[xyValues[i].x] {
+				result[i%s.numRows] = true
+			}
+		}
+	}
+	if len(result) > 0 {
+		result[0] = false
+	}
+	return result
 }
 
-func createXYFormat(xwidth, ywidth int) string {
-	return "|%" + strconv.Itoa(xwidth) + "s|%" + strconv.Itoa(ywidth) + "s"
+func createHeader(widths []int, numCols int) string {
+	var piece strings.Builder
+	for _, width := range widths {
+		piece.WriteString("+")
+		piece.WriteString(strings.Repeat("-", width))
+	}
+	return fmt.Sprintf("%s+", strings.Repeat(piece.String(), numCols))
 }
 
 // WriteTo writes the chart to writer w. If w is nil, WriteTo writes the
@@ -268,30 +432,71 @@ func (c *Chart) WriteTo(w io.Writer) (n int, err error) {
 	if w == nil {
 		w = os.Stdout
 	}
-	var nn int
-	nn, err = fmt.Fprintln(w, c.header)
-	n += nn
-	if err != nil {
+	cw := &chartWriter{
+		w:      w,
+		styled: c.styled && (c.alwaysStyle || isTerminal(w)),
+	}
+	c.write(cw)
+	return cw.n, cw.err
+}
+
+// write writes this chart as text to cw.
+func (c *Chart) write(cw *chartWriter) {
+	if c.transposeWidth > 0 {
+		c.writeTransposed(cw)
 		return
 	}
+	cw.println(c.header)
 	for i := 0; i < c.numRows; i++ {
-		for j := 0; j < c.numCols; j++ {
-			xyValue := c.xy(i, j)
-			nn, err = fmt.Fprintf(w, c.xyFormat, xyValue.x, xyValue.y)
-			n += nn
-			if err != nil {
-				return
-			}
+		if c.rules[i] {
+			cw.println(c.header)
 		}
-		nn, err = fmt.Fprintln(w, "|")
-		n += nn
-		if err != nil {
-			return
+		c.writeRow(cw, func(col int) xyValueType {
+			return c.xy(i, col)
+		})
+	}
+	if len(c.footer) > 0 {
+		cw.println(c.header)
+		footerRows := (len(c.footer) + c.numCols - 1) / c.numCols
+		for i := 0; i < footerRows; i++ {
+			c.writeRow(cw, func(col int) xyValueType {
+				var result xyValueType
+				if idx := i*c.numCols + col; idx < len(c.footer) {
+					result = c.footer[idx]
+				}
+				return result
+			})
+		}
+	}
+	cw.println(c.header)
+}
+
+// writeRow writes one row of this chart to cw. xyAt returns the X and Y
+// values for each column of the row.
+func (c *Chart) writeRow(cw *chartWriter, xyAt func(col int) xyValueType) {
+	xyValues := make(xyValuesType, c.numCols)
+	lines := make([][][]string, c.numCols)
+	numLines := 1
+	for j := range xyValues {
+		xyValues[j] = xyAt(j)
+		lines[j] = make([][]string, len(c.widths))
+		for k := range c.widths {
+			lines[j][k] = splitCell(xyValues[j].cell(k), c.wrapWidth)
+			numLines = maxInt(numLines, len(lines[j][k]))
 		}
 	}
-	nn, err = fmt.Fprintln(w, c.header)
-	n += nn
-	return
+	for l := 0; l < numLines; l++ {
+		for j := range xyValues {
+			for k, width := range c.widths {
+				line := lineAt(lines[j][k], l)
+				if k > 0 {
+					line = cw.style(line, width, xyValues[j].style)
+				}
+				cw.printf("|%*s", width, line)
+			}
+		}
+		cw.println("|")
+	}
 }
 
 // NumRows returns the number of rows in this chart.
@@ -315,31 +520,126 @@ func (c *Chart) xy(row, col int) xyValueType {
 
 type xyValueType struct {
 	x string
-	y string
+
+	// Usually just one Y value, but some options split a Y value
+	// across several cells.
+	ys []string
+
+	// The ANSI escape sequence for styling ys, if any.
+	style string
+}
+
+// cell returns the X value if idx is 0 or else the (idx-1)th Y value.
+// cell returns the empty string if there is no such value.
+func (xy *xyValueType) cell(idx int) string {
+	if idx == 0 {
+		return xy.x
+	}
+	return lineAt(xy.ys, idx-1)
 }
 
 type xyValuesType []xyValueType
 
-func createXYValues(
-	xs, ys Values, xformat, yformat string) xyValuesType {
+func createXYValues(xs, ys Values, s *settingsType) xyValuesType {
 	result := make(xyValuesType, xs.Len())
 	for i := 0; i < xs.Len(); i++ {
-		result[i].x = fmt.Sprintf(xformat, xs.Value(i))
-		result[i].y = fmt.Sprintf(yformat, ys.Value(i))
+		x, y := xs.Value(i), ys.Value(i)
+		result[i].x = s.format(s.xFormat, x)
+		result[i].ys = s.formatY(y, i)
+		if s.styles != nil {
+			result[i].style = s.styles[i]
+		} else {
+			result[i].style = s.styleOf(x, y)
+		}
 	}
 	return result
 }
 
-func (xy xyValuesType) widths() (xwidth int, ywidth int) {
-	for i := 0; i < len(xy); i++ {
-		if len(xy[i].x) > xwidth {
-			xwidth = len(xy[i].x)
+// widths returns the width of the X cell followed by the widths of the
+// numYs Y cells.
+func (xy xyValuesType) widths(numYs, wrapWidth int) []int {
+	result := make([]int, numYs+1)
+	for i := range xy {
+		for j := range result {
+			result[j] = maxInt(result[j], cellWidth(xy[i].cell(j)))
 		}
-		if len(xy[i].y) > ywidth {
-			ywidth = len(xy[i].y)
+	}
+	if wrapWidth > 0 {
+		for j := range result {
+			result[j] = minInt(result[j], wrapWidth)
 		}
 	}
-	return
+	return result
+}
+
+// cellWidth returns how many characters wide s is.
+func cellWidth(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// splitCell splits s into lines no wider than width. The first line gets
+// any leftover characters so that the last line ends with the last
+// characters of s. If width <= 0, splitCell does not split s.
+func splitCell(s string, width int) []string {
+	if width <= 0 || cellWidth(s) <= width {
+		return []string{s}
+	}
+	runes := []rune(s)
+	first := len(runes) % width
+	if first == 0 {
+		first = width
+	}
+	result := []string{string(runes[:first])}
+	for i := first; i < len(runes); i += width {
+		result = append(result, string(runes[i:i+width]))
+	}
+	return result
+}
+
+func lineAt(lines []string, idx int) string {
+	if idx < len(lines) {
+		return lines[idx]
+	}
+	return ""
+}
+
+type chartWriter struct {
+	w      io.Writer
+	styled bool
+	n      int
+	err    error
+}
+
+// style returns text padded to width and styled with style if this writer
+// writes styles and text is not empty. Otherwise, style returns text.
+func (c *chartWriter) style(text string, width int, style string) string {
+	if !c.styled || text == "" {
+		return text
+	}
+	return styleCell(text, width, style)
+}
+
+func (c *chartWriter) printf(format string, args ...interface{}) {
+	if c.err != nil {
+		return
+	}
+	var nn int
+	nn, c.err = fmt.Fprintf(c.w, format, args...)
+	c.n += nn
+}
+
+func (c *chartWriter) println(args ...interface{}) {
+	if c.err != nil {
+		return
+	}
+	var nn int
+	nn, c.err = fmt.Fprintln(c.w, args...)
+	c.n += nn
+}
+
+type columnType struct {
+	values Values
+	format string
 }
 
 type optionFunc func(s *settingsType)
@@ -349,10 +649,99 @@ func (o optionFunc) mutate(s *settingsType) {
 }
 
 type settingsType struct {
-	xFormat string
-	yFormat string
-	numRows int
-	numCols int
+	xFormat        string
+	yFormat        string
+	numRows        int
+	numCols        int
+	wrapWidth      int
+	ruleEvery      int
+	rulesAt        []interface{}
+	footer         []Stat
+	transposeWidth int
+	highlights     []highlightType
+	alwaysStyle    bool
+	ratFormat      func(r *big.Rat) string
+	complexFormat  func(c complex128) string
+	splitComplex   bool
+	timeLayout     string
+	columns        []columnType
+
+	// Describe ratFormat and complexFormat for marshaling.
+	ratFormatSpec     formatSpec
+	complexFormatSpec formatSpec
+
+	// If non nil, the styles of the Y values by index. Used in place of
+	// highlights when unmarshaling.
+	styles []string
+
+	// Context lines for CompareValues. Negative means show all X values.
+	context int
+}
+
+// format formats value with fmtStr unless an option gives the type of
+// value its own formatting.
+func (s *settingsType) format(fmtStr string, value interface{}) string {
+	switch v := value.(type) {
+	case missingType:
+		return ""
+	case *big.Rat:
+		if s.ratFormat != nil {
+			return s.ratFormat(v)
+		}
+	case complex128:
+		if s.complexFormat != nil {
+			return s.complexFormat(v)
+		}
+	case time.Time:
+		if s.timeLayout != "" {
+			return v.Format(s.timeLayout)
+		}
+	}
+	return fmt.Sprintf(fmtStr, value)
+}
+
+// formatY formats the idx Y value, y, into one string for each Y cell.
+func (s *settingsType) formatY(y interface{}, idx int) []string {
+	result := make([]string, 0, s.numYs())
+	if c, ok := y.(complex128); ok && s.splitComplex {
+		result = append(
+			result,
+			s.format(s.yFormat, real(c)),
+			s.format(s.yFormat, imag(c)))
+	} else if s.splitComplex {
+		result = append(result, s.format(s.yFormat, y), "")
+	} else {
+		result = append(result, s.format(s.yFormat, y))
+	}
+	for _, column := range s.columns {
+		result = append(result, s.format(column.format, column.values.Value(idx)))
+	}
+	return result
+}
+
+// formatOptions returns options that format values the same way as s.
+func (s *settingsType) formatOptions() Options {
+	result := Options{XFormat(s.xFormat), YFormat(s.yFormat)}
+	if s.timeLayout != "" {
+		result = append(result, TimeLayout(s.timeLayout))
+	}
+	if s.ratFormat != nil {
+		result = append(result, ratFormat(s.ratFormatSpec, s.ratFormat))
+	}
+	if s.complexFormat != nil {
+		result = append(
+			result, complexFormat(s.complexFormatSpec, s.complexFormat))
+	}
+	return result
+}
+
+// numYs returns the number of Y cells for each X value.
+func (s *settingsType) numYs() int {
+	result := 1
+	if s.splitComplex {
+		result++
+	}
+	return result + len(s.columns)
 }
 
 func (s *settingsType) computeDimensions(count int) {
@@ -383,3 +772,31 @@ func (v valueSlice) Value(idx int) interface{} {
 func (v valueSlice) Len() int {
 	return len(v)
 }
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func maxInt(x int, rest ...int) int {
+	for _, y := range rest {
+		if y > x {
+			x = y
+		}
+	}
+	return x
+}
+
+// powInt returns x raised to the nonnegative power n.
+func powInt(x int64, n int) int64 {
+	result := int64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result *= x
+		}
+		x *= x
+	}
+	return result
+}
@@ -1,9 +1,12 @@
 package gochart_test
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/keep94/gochart"
 	"github.com/keep94/gomath"
@@ -16,6 +19,41 @@ func TestApply(t *testing.T) {
 	assertValuesEqual(t, ys, int64(15), int64(21), int64(27))
 }
 
+func TestGeometricInts(t *testing.T) {
+	xs := gochart.NewGeometricInts(3, 2, 5)
+	assertValuesEqual(
+		t, xs, int64(3), int64(6), int64(12), int64(24), int64(48))
+	ys := xs.ApplyStream(to30By2())
+	assertValuesEqual(
+		t, ys, int64(6), int64(12), int64(24), int64(0), int64(0))
+}
+
+func TestGeometricFloats(t *testing.T) {
+	xs := gochart.NewGeometricFloats(0.5, 2.0, 4)
+	assertValuesEqual(t, xs, 0.5, 1.0, 2.0, 4.0)
+	ys := xs.Apply(func(x float64) float64 { return x * x })
+	assertValuesEqual(t, ys, 0.25, 1.0, 4.0, 16.0)
+}
+
+func TestLogFloats(t *testing.T) {
+	xs := gochart.NewLogFloats(1e-3, 1e3, 7)
+	assertValuesEqual(t, xs, 1e-3, 1e-2, 1e-1, 1.0, 1e1, 1e2, 1e3)
+	xs = gochart.NewLogFloats(2, 2000, 4)
+	assertValuesEqual(t, xs, 2.0, 20.0, 200.0, 2000.0)
+	xs = gochart.NewLogFloats(3, 7, 5)
+	assertEqual(t, 3.0, xs.Value(0))
+	assertCloseTo(t, 3.0*math.Pow(7.0/3.0, 0.5), xs.Value(2).(float64))
+	assertEqual(t, 7.0, xs.Value(4))
+	xs = gochart.NewLogFloats(5, 100, 1)
+	assertValuesEqual(t, xs, 5.0)
+	xs = gochart.NewLogFloats(1.0, 16.0, 3)
+	assertCloseTo(t, 4.0, xs.Value(1).(float64))
+	ys := xs.ApplyInv(func(x float64) float64 { return x * x }, 0.0, 5.0)
+	assertCloseTo(t, 1.0, ys.Value(0).(float64))
+	assertCloseTo(t, 2.0, ys.Value(1).(float64))
+	assertCloseTo(t, 4.0, ys.Value(2).(float64))
+}
+
 func TestApplyBigInt(t *testing.T) {
 	xs := gochart.NewInts(10, 1, 4)
 	ys := xs.ApplyBigInt(
@@ -102,6 +140,137 @@ func TestApplyStreamDown(t *testing.T) {
 	})
 }
 
+func TestBigInts(t *testing.T) {
+	start, _ := new(big.Int).SetString("1000000000000000000000000000000", 10)
+	xs := gochart.NewBigInts(start, big.NewInt(7), 3)
+	assertEqual(t, 3, xs.Len())
+	assertEqual(
+		t, "1000000000000000000000000000014", fmt.Sprint(xs.Value(2)))
+	ys := xs.Apply(func(x *big.Int) int64 {
+		return new(big.Int).Mod(x, big.NewInt(10)).Int64()
+	})
+	assertValuesEqual(t, ys, int64(0), int64(7), int64(4))
+	ys = xs.ApplyBigInt(func(x *big.Int, result *big.Int) *big.Int {
+		return result.Sub(x, start)
+	})
+	assertBigValuesEqual(t, ys, 0, 7, 14)
+	assertPanic(t, func() { xs.Value(3) })
+}
+
+func TestBigFloats(t *testing.T) {
+	prec := uint(200)
+	xs := gochart.NewBigFloats(
+		new(big.Float).SetPrec(prec).SetInt64(2),
+		big.NewFloat(1.0),
+		3)
+	ys := xs.ApplyInv(
+		func(x *big.Float, result *big.Float) *big.Float {
+			return result.Mul(x, x)
+		},
+		big.NewFloat(0.0),
+		big.NewFloat(2.0))
+	chart := gochart.NewChart(xs, ys, gochart.YFormat("%.40f"))
+	assertChart(
+		t,
+		chart,
+		"+-+------------------------------------------+",
+		"|2|1.4142135623730950488016887242096980785697|",
+		"|3|1.7320508075688772935274463415058723669428|",
+		"|4|2.0000000000000000000000000000000000000000|",
+		"+-+------------------------------------------+",
+	)
+	squares := xs.Apply(func(x *big.Float, result *big.Float) *big.Float {
+		return result.Mul(x, x)
+	})
+	assertEqual(t, prec, squares.Value(0).(*big.Float).Prec())
+	assertEqual(t, "9", fmt.Sprint(squares.Value(1)))
+}
+
+func TestBigFloatsInverseExactRoot(t *testing.T) {
+	square := func(x *big.Float, result *big.Float) *big.Float {
+		return result.Mul(x, x)
+	}
+	identity := func(x *big.Float, result *big.Float) *big.Float {
+		return result.Set(x)
+	}
+	xs := gochart.NewBigFloats(big.NewFloat(0), big.NewFloat(1), 1)
+	ys := xs.ApplyInv(square, big.NewFloat(0), big.NewFloat(1))
+	assertEqual(t, 0, ys.Value(0).(*big.Float).Sign())
+	ys = xs.ApplyInv(identity, big.NewFloat(-1), big.NewFloat(1))
+	assertEqual(t, 0, ys.Value(0).(*big.Float).Sign())
+
+	// Bisection never lands on 0 exactly here but still stops.
+	ys = xs.ApplyInv(identity, big.NewFloat(-1), big.NewFloat(0.75))
+	y, _ := ys.Value(0).(*big.Float).Float64()
+	if math.Abs(y) > 1e-30 {
+		t.Errorf("Expected about 0, got %v", y)
+	}
+}
+
+func TestApplyRat(t *testing.T) {
+	xs := gochart.NewInts(-2, 1, 5)
+	ys := xs.ApplyRat(func(x int64, result *big.Rat) *big.Rat {
+		return result.SetFrac64(5*x, 4)
+	})
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, gochart.NumCols(5)),
+		"+--+----+--+----+--+----+--+----+--+----+",
+		"|-2|-5/2|-1|-5/4| 0| 0/1| 1| 5/4| 2| 5/2|",
+		"+--+----+--+----+--+----+--+----+--+----+",
+	)
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, gochart.NumCols(5), gochart.RatFraction()),
+		"+--+----+--+----+--+----+--+----+--+----+",
+		"|-2|-5/2|-1|-5/4| 0|   0| 1| 5/4| 2| 5/2|",
+		"+--+----+--+----+--+----+--+----+--+----+",
+	)
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, gochart.NumCols(5), gochart.RatMixed()),
+		"+--+------+--+------+--+------+--+------+--+------+",
+		"|-2|-2 1/2|-1|-1 1/4| 0|     0| 1| 1 1/4| 2| 2 1/2|",
+		"+--+------+--+------+--+------+--+------+--+------+",
+	)
+	assertChart(
+		t,
+		gochart.NewChart(
+			xs,
+			ys,
+			gochart.RatDecimal(2),
+			gochart.Footer(gochart.StatMin, gochart.StatSum, gochart.StatMean)),
+		"+----+-----+",
+		"|  -2|-2.50|",
+		"|  -1|-1.25|",
+		"|   0| 0.00|",
+		"|   1| 1.25|",
+		"|   2| 2.50|",
+		"+----+-----+",
+		"| min|-2.50|",
+		"| sum| 0.00|",
+		"|mean|    0|",
+		"+----+-----+",
+	)
+}
+
+func TestFooterEmpty(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 0)
+	assertChart(
+		t,
+		gochart.NewChart(
+			xs,
+			xs,
+			gochart.YFormat("%.2f"),
+			gochart.Footer(gochart.StatCount, gochart.StatSum)),
+		"+-----+-+",
+		"+-----+-+",
+		"|count|0|",
+		"|  sum| |",
+		"+-----+-+",
+	)
+}
+
 func TestApplyFloat(t *testing.T) {
 	xs := gochart.NewFloats(1.0, 2.0, 4)
 	ys := xs.Apply(func(x float64) float64 {
@@ -159,6 +328,90 @@ func TestApplyInvFloatReverse(t *testing.T) {
 	}
 }
 
+func TestApplyComplex(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 3)
+	ys := xs.ApplyComplex(func(x float64) complex128 {
+		return complex(x, -x)
+	})
+	assertValuesEqual(t, ys, 1-1i, 2-2i, 3-3i)
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, gochart.ComplexRect(1)),
+		"+-+--------+",
+		"|1|1.0-1.0i|",
+		"|2|2.0-2.0i|",
+		"|3|3.0-3.0i|",
+		"+-+--------+",
+	)
+	assertChart(
+		t,
+		gochart.NewChart(
+			xs, ys, gochart.ComplexPolar(2), gochart.NumCols(2)),
+		"+-+----------+-+----------+",
+		"|1|1.41∠-0.79|3|4.24∠-0.79|",
+		"|2|2.83∠-0.79| |          |",
+		"+-+----------+-+----------+",
+	)
+}
+
+func TestSplitComplexTranspose(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 2)
+	ys := xs.ApplyComplex(func(x float64) complex128 {
+		return complex(x, 10*x)
+	})
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, gochart.SplitComplex(), gochart.Transpose(80)),
+		"+--+--+",
+		"| 1| 2|",
+		"| 1| 2|",
+		"|10|20|",
+		"+--+--+",
+	)
+}
+
+func TestTimes(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	xs := gochart.NewTimes(start, gochart.Every(36*time.Hour), 3)
+	assertValuesEqual(
+		t,
+		xs,
+		start,
+		time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC))
+	xs = gochart.NewTimes(start, gochart.EveryDate(1, 1, 1), 2)
+	assertValuesEqual(
+		t, xs, start, time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC))
+	assertPanic(t, func() { xs.Value(2) })
+}
+
+func TestParseTimeStep(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []struct {
+		step     string
+		expected time.Time
+	}{
+		{"1 year", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2 Months", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2 weeks", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"1 day", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"1h30m", time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC)},
+	}
+	for _, s := range steps {
+		step, err := gochart.ParseTimeStep(s.step)
+		if err != nil {
+			t.Fatalf("Got error parsing %q: %v", s.step, err)
+		}
+		xs := gochart.NewTimes(start, step, 2)
+		assertEqual(t, s.expected, xs.Value(1))
+	}
+	for _, bad := range []string{"", "month", "x months", "1 fortnight"} {
+		if _, err := gochart.ParseTimeStep(bad); err == nil {
+			t.Errorf("Expected error parsing %q", bad)
+		}
+	}
+}
+
 func TestChartDimensions(t *testing.T) {
 	xs := gochart.NewInts(1, 1, 100)
 	chart := gochart.NewChart(xs, xs)
@@ -183,6 +436,187 @@ func TestOptions(t *testing.T) {
 	assertEqual(t, 6, chart.NumCols())
 }
 
+func TestWrapWidth(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 4)
+	ys := gochart.NewInts(123456, 1000000, 4)
+	chart := gochart.NewChart(xs, ys, gochart.NumCols(2), gochart.WrapWidth(4))
+	assertChart(
+		t,
+		chart,
+		"+-+----+-+----+",
+		"|1|  12|3| 212|",
+		"| |3456| |3456|",
+		"|2| 112|4| 312|",
+		"| |3456| |3456|",
+		"+-+----+-+----+",
+	)
+}
+
+func TestRuleEvery(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 7)
+	chart := gochart.NewChart(xs, xs, gochart.RuleEvery(3))
+	assertChart(
+		t,
+		chart,
+		"+-+-+",
+		"|1|1|",
+		"|2|2|",
+		"|3|3|",
+		"+-+-+",
+		"|4|4|",
+		"|5|5|",
+		"|6|6|",
+		"+-+-+",
+		"|7|7|",
+		"+-+-+",
+	)
+}
+
+func TestRulesAt(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 8)
+	chart := gochart.NewChart(
+		xs, xs, gochart.NumCols(2), gochart.RulesAt(1, 3, 8))
+	assertChart(
+		t,
+		chart,
+		"+-+-+-+-+",
+		"|1|1|5|5|",
+		"|2|2|6|6|",
+		"+-+-+-+-+",
+		"|3|3|7|7|",
+		"+-+-+-+-+",
+		"|4|4|8|8|",
+		"+-+-+-+-+",
+	)
+}
+
+func TestFooter(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 4)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.NumCols(2),
+		gochart.Footer(
+			gochart.StatCount,
+			gochart.StatMin,
+			gochart.StatMax,
+			gochart.StatMean))
+	assertChart(
+		t,
+		chart,
+		"+-----+----+-----+----+",
+		"|    1|   1|    3|   9|",
+		"|    2|   4|    4|  16|",
+		"+-----+----+-----+----+",
+		"|count|   4|  min|   1|",
+		"|  max|  16| mean|15/2|",
+		"+-----+----+-----+----+",
+	)
+}
+
+func TestFooterBigInt(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	ys := xs.ApplyBigInt(
+		func(x int64, result *big.Int) *big.Int {
+			return result.Exp(big.NewInt(10), big.NewInt(6*x), nil)
+		})
+	chart := gochart.NewChart(
+		xs, ys, gochart.Footer(gochart.StatSum, gochart.StatMean))
+	assertChart(
+		t,
+		chart,
+		"+----+-------------------+",
+		"|   1|            1000000|",
+		"|   2|      1000000000000|",
+		"|   3|1000000000000000000|",
+		"+----+-------------------+",
+		"| sum|1000001000001000000|",
+		"|mean| 333333666667000000|",
+		"+----+-------------------+",
+	)
+}
+
+func TestFooterFloat(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 4)
+	ys := xs.Apply(func(x float64) float64 { return x / 2.0 })
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.YFormat("%.2f"),
+		gochart.Footer(gochart.StatSum, gochart.StatMean))
+	assertChart(
+		t,
+		chart,
+		"+----+----+",
+		"|   1|0.50|",
+		"|   2|1.00|",
+		"|   3|1.50|",
+		"|   4|2.00|",
+		"+----+----+",
+		"| sum|5.00|",
+		"|mean|1.25|",
+		"+----+----+",
+	)
+}
+
+func TestFooterPanic(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	assertPanic(t, func() {
+		gochart.NewChart(xs, xs, gochart.Footer(gochart.Stat(7)))
+	})
+}
+
+func TestTransposeFooter(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	chart := gochart.NewChart(
+		xs, xs, gochart.Transpose(80), gochart.Footer(gochart.StatSum))
+	assertChart(
+		t,
+		chart,
+		"+-+-+-+",
+		"|1|2|3|",
+		"|1|2|3|",
+		"+-+-+-+",
+		"",
+		"+---+",
+		"|sum|",
+		"|  6|",
+		"+---+",
+	)
+}
+
+func TestHighlight(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 4)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	isEven := func(x, y interface{}) bool { return y.(int64)%2 == 0 }
+	isBig := func(x, y interface{}) bool { return y.(int64) > 10 }
+	options := gochart.Options{
+		gochart.Highlight(isEven, gochart.Bold),
+		gochart.Highlight(isBig, gochart.Red),
+	}
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, options),
+		"+-+--+",
+		"|1| 1|",
+		"|2| 4|",
+		"|3| 9|",
+		"|4|16|",
+		"+-+--+",
+	)
+	assertChart(
+		t,
+		gochart.NewChart(xs, ys, options, gochart.AlwaysStyle()),
+		"+-+--+",
+		"|1| 1|",
+		"|2|\x1b[1m 4\x1b[0m|",
+		"|3| 9|",
+		"|4|\x1b[1;31m16\x1b[0m|",
+		"+-+--+",
+	)
+}
+
 func TestNewChartPanic(t *testing.T) {
 	xs := gochart.NewInts(1, 1, 10)
 	ys := gochart.NewInts(1, 1, 9)
@@ -226,6 +660,16 @@ func assertValuesEqual(
 	}
 }
 
+func assertChart(t *testing.T, chart *gochart.Chart, expectedLines ...string) {
+	t.Helper()
+	var builder strings.Builder
+	chart.WriteTo(&builder)
+	expected := strings.Join(expectedLines, "\n") + "\n"
+	if actual := builder.String(); actual != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
 func assertEqual(
 	t *testing.T, expected, actual interface{}) {
 	t.Helper()
@@ -0,0 +1,227 @@
+// Package charthttp serves charts over HTTP.
+package charthttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/internal/chartspec"
+)
+
+const (
+	kDefaultMaxCount = 1000
+	kDefaultTimeout  = 5 * time.Second
+	kHTMLHeader      = "<!DOCTYPE html>\n<html>\n<body>\n"
+	kHTMLFooter      = "</body>\n</html>\n"
+)
+
+// Handler serves charts of an expression in x or of a named sequence.
+// Handler accepts these query parameters, all optional except expr or seq:
+//
+//	expr     an expression in x as package expr accepts
+//	seq      a sequence name such as partitions or ugly(3,5,7)
+//	start    the first x value, default 1
+//	step     the increment between x values, default 1
+//	count    the number of x values, default 10
+//	float    if true, use floating point arithmetic for expr
+//	big      if true, use integer arithmetic for expr that never overflows
+//	rows     the number of rows, default as needed
+//	cols     the number of columns, default as needed
+//	xdigits  the number of fraction digits for floating point x values
+//	ydigits  the number of fraction digits for floating point y values
+//	format   text, html, csv, markdown, json, or svg; default text
+//
+// The html format is a complete page holding a table. Handler responds
+// with 400 Bad Request for invalid parameters and with
+// 503 Service Unavailable if computing the chart takes too long or if
+// too many charts are being computed already. Handler stops evaluating
+// an expression at the next X value once it takes too long, but
+// sequences cannot be interrupted: a sequence that takes too long keeps
+// running in the background until it finishes and counts against
+// MaxConcurrent until then. A Handler must not be copied after first
+// use.
+type Handler struct {
+
+	// The maximum number of X values, rows, and columns. 0 means 1000.
+	// Rows times columns may exceed the number of X values by at most
+	// MaxCount.
+	MaxCount int
+
+	// The maximum time to compute a chart. 0 means 5 seconds.
+	Timeout time.Duration
+
+	// The maximum number of charts to compute at once. 0 means the
+	// number of CPUs.
+	MaxConcurrent int
+
+	// The registry for looking up sequences. nil means gochart.Sequences.
+	Sequences *gochart.SequenceRegistry
+
+	once  sync.Once
+	slots chan struct{}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spec, formatName, err := h.parse(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.once.Do(h.init)
+	select {
+	case h.slots <- struct{}{}:
+	default:
+		http.Error(
+			w, "gochart: too many charts in progress",
+			http.StatusServiceUnavailable)
+		return
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = kDefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	spec.Done = ctx.Done()
+	type resultType struct {
+		body []byte
+		err  error
+	}
+	done := make(chan resultType, 1)
+	go func() {
+		defer func() { <-h.slots }()
+		var result resultType
+		chart, err := spec.Chart()
+		if err == nil {
+			var buf bytes.Buffer
+			if formatName == "html" {
+				buf.WriteString(kHTMLHeader)
+			}
+			err = chartspec.Formats[formatName].Write(chart, &buf)
+			if formatName == "html" {
+				buf.WriteString(kHTMLFooter)
+			}
+			result.body = buf.Bytes()
+		}
+		result.err = err
+		done <- result
+	}()
+	select {
+	case result := <-done:
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", chartspec.Formats[formatName].ContentType)
+		w.Write(result.body)
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			http.Error(
+				w, "gochart: computation timed out",
+				http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func (h *Handler) init() {
+	maxConcurrent := h.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = runtime.NumCPU()
+	}
+	h.slots = make(chan struct{}, maxConcurrent)
+}
+
+// parse returns the chart and the name of the format that query
+// describes.
+func (h *Handler) parse(query url.Values) (*chartspec.Spec, string, error) {
+	spec := &chartspec.Spec{
+		Start:     stringParam(query, "start", "1"),
+		Step:      stringParam(query, "step", "1"),
+		Expr:      query.Get("expr"),
+		Seq:       query.Get("seq"),
+		Sequences: h.Sequences,
+	}
+	p := &paramParser{query: query}
+	spec.Count = p.intParam("count", 10)
+	spec.Rows = p.intParam("rows", 0)
+	spec.Cols = p.intParam("cols", 0)
+	spec.XDigits = p.intParam("xdigits", -1)
+	spec.YDigits = p.intParam("ydigits", -1)
+	spec.Float = p.boolParam("float")
+	spec.Big = p.boolParam("big")
+	if p.err != nil {
+		return nil, "", p.err
+	}
+	maxCount := h.MaxCount
+	if maxCount <= 0 {
+		maxCount = kDefaultMaxCount
+	}
+	for _, limited := range []struct {
+		name  string
+		value int
+	}{{"count", spec.Count}, {"rows", spec.Rows}, {"cols", spec.Cols}} {
+		if limited.value > maxCount {
+			return nil, "", fmt.Errorf(
+				"gochart: %s %d exceeds limit of %d",
+				limited.name, limited.value, maxCount)
+		}
+	}
+
+	// Blank cells cost as much to render as cells with values.
+	if spec.Rows > 0 && spec.Cols > 0 &&
+		spec.Rows*spec.Cols > spec.Count+maxCount {
+		return nil, "", fmt.Errorf(
+			"gochart: %d rows and %d columns is too many for %d values",
+			spec.Rows, spec.Cols, spec.Count)
+	}
+	formatName := stringParam(query, "format", "text")
+	if _, ok := chartspec.Formats[formatName]; !ok {
+		return nil, "", fmt.Errorf("gochart: unknown format %q", formatName)
+	}
+	return spec, formatName, nil
+}
+
+func stringParam(query url.Values, name, defaultValue string) string {
+	if result := query.Get(name); result != "" {
+		return result
+	}
+	return defaultValue
+}
+
+// paramParser parses query parameters remembering the first error.
+type paramParser struct {
+	query url.Values
+	err   error
+}
+
+func (p *paramParser) intParam(name string, defaultValue int) int {
+	s := p.query.Get(name)
+	if s == "" {
+		return defaultValue
+	}
+	result, err := strconv.Atoi(s)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("gochart: invalid %s %q", name, s)
+	}
+	return result
+}
+
+func (p *paramParser) boolParam(name string) bool {
+	s := p.query.Get(name)
+	if s == "" {
+		return false
+	}
+	result, err := strconv.ParseBool(s)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("gochart: invalid %s %q", name, s)
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package charthttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/charthttp"
+)
+
+func TestText(t *testing.T) {
+	code, contentType, body := get(
+		t, &charthttp.Handler{}, "/?expr=x%5E2&count=4&cols=2")
+	assertEqual(t, http.StatusOK, code)
+	assertEqual(t, "text/plain; charset=utf-8", contentType)
+	assertEqual(t, "+-+--+-+--+\n|1| 1|3| 9|\n|2| 4|4|16|\n+-+--+-+--+\n", body)
+}
+
+func TestSequence(t *testing.T) {
+	code, contentType, body := get(
+		t, &charthttp.Handler{}, "/?seq=ugly(2,3)&count=3&format=csv")
+	assertEqual(t, http.StatusOK, code)
+	assertEqual(t, "text/csv; charset=utf-8", contentType)
+	assertEqual(t, "1,1\n2,2\n3,3\n", body)
+}
+
+func TestFormats(t *testing.T) {
+	h := &charthttp.Handler{}
+	_, contentType, body := get(
+		t, h, "/?expr=x/2&start=1&step=1&float=true&count=2&ydigits=1&format=json")
+	assertEqual(t, "application/json", contentType)
+	assertEqual(t, "[[\"1\",\"0.5\"],[\"2\",\"1.0\"]]\n", body)
+	_, _, body = get(t, h, "/?expr=x*2&count=2&xdigits=1&ydigits=2&format=csv")
+	assertEqual(t, "1,2\n2,4\n", body)
+	_, contentType, body = get(t, h, "/?expr=x&count=2&format=html")
+	assertEqual(t, "text/html; charset=utf-8", contentType)
+	if !strings.HasPrefix(body, "<!DOCTYPE html>") ||
+		!strings.Contains(body, "<tr><td>2</td><td>2</td></tr>") {
+		t.Errorf("Unexpected html: %s", body)
+	}
+	_, contentType, body = get(t, h, "/?expr=x&count=2&format=svg")
+	assertEqual(t, "image/svg+xml", contentType)
+	if !strings.HasPrefix(body, "<svg ") {
+		t.Errorf("Unexpected svg: %s", body)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	h := &charthttp.Handler{MaxCount: 100}
+	for _, url := range []string{
+		"/",
+		"/?expr=x&seq=primes",
+		"/?expr=x%2B",
+		"/?expr=x&count=101",
+		"/?expr=x&rows=101",
+		"/?expr=x&count=1&rows=100&cols=100",
+		"/?expr=x&count=abc",
+		"/?expr=x&float=maybe",
+		"/?expr=x&format=pdf",
+		"/?expr=1/x&start=0",
+		"/?seq=nosuch",
+		"/?seq=primes&start=0",
+	} {
+		if code, _, _ := get(t, h, url); code != http.StatusBadRequest {
+			t.Errorf("%s: expected %d, got %d", url, http.StatusBadRequest, code)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	h := &charthttp.Handler{
+		Timeout:   10 * time.Millisecond,
+		Sequences: slowSequences(),
+	}
+	code, _, _ := get(t, h, "/?seq=slow")
+	assertEqual(t, http.StatusServiceUnavailable, code)
+}
+
+func TestRunawayRequests(t *testing.T) {
+	h := &charthttp.Handler{}
+	for _, url := range []string{
+		"/?expr=2%5E(10%5E15)&big=true",
+		"/?expr=fact(10%5E12)&big=true",
+		"/?expr=binomial(10%5E12,10%5E11)",
+		"/?seq=products(100,10)",
+		"/?seq=factors(9223372036854775783)",
+	} {
+		if code, _, _ := get(t, h, url); code != http.StatusBadRequest {
+			t.Errorf("%s: expected %d, got %d", url, http.StatusBadRequest, code)
+		}
+	}
+	code, _, _ := get(t, h, "/?expr=x&count=1&rows=1000&cols=1000&format=html")
+	assertEqual(t, http.StatusBadRequest, code)
+	code, _, _ = get(t, h, "/?expr=x&count=3&rows=2&cols=2")
+	assertEqual(t, http.StatusOK, code)
+	code, _, body := get(t, h, "/?expr=fact(10%5E12)&count=1&format=csv")
+	assertEqual(t, http.StatusOK, code)
+	assertEqual(t, "1,0\n", body)
+}
+
+func TestTimeoutCancelsExpression(t *testing.T) {
+	h := &charthttp.Handler{
+		Timeout:       10 * time.Millisecond,
+		MaxConcurrent: 1,
+	}
+	code, _, _ := get(t, h, "/?expr=fact(50000)&big=true&count=1000")
+	assertEqual(t, http.StatusServiceUnavailable, code)
+
+	// The canceled computation gives up its slot shortly.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		code, _, _ = get(t, h, "/?expr=x")
+		if code == http.StatusOK || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEqual(t, http.StatusOK, code)
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	h := &charthttp.Handler{
+		Timeout:       10 * time.Millisecond,
+		MaxConcurrent: 1,
+		Sequences:     slowSequences(),
+	}
+	code, _, _ := get(t, h, "/?seq=slow")
+	assertEqual(t, http.StatusServiceUnavailable, code)
+	code, _, body := get(t, h, "/?seq=slow")
+	assertEqual(t, http.StatusServiceUnavailable, code)
+	if !strings.Contains(body, "too many") {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func slowSequences() *gochart.SequenceRegistry {
+	registry := gochart.NewSequenceRegistry()
+	registry.Register(
+		"slow",
+		"slow: takes a long time",
+		func(params []int64) (gochart.Sequence, error) {
+			return gochart.SequenceFunc(func(xs *gochart.Ints) gochart.Values {
+				time.Sleep(time.Second)
+				return xs
+			}), nil
+		})
+	return registry
+}
+
+func get(t *testing.T, h http.Handler, url string) (
+	code int, contentType, body string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w.Code, w.Header().Get("Content-Type"), w.Body.String()
+}
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,165 @@
+// Package charttemplate provides template functions for building and
+// rendering charts from text/template and html/template.
+//
+// For example, this template renders the first 10 partition numbers as an
+// HTML table:
+//
+//	{{ $xs := ints 1 1 10 }}
+//	{{ chart $xs (apply "partitions" $xs) (cols 2) | chartHTML }}
+//
+// The functions are:
+//
+//	ints start step count        integer X values like gochart.NewInts
+//	floats start step count      float64 X values like gochart.NewFloats
+//	apply name xs                the sequence name in Funcs.Sequences
+//	                             applied to integer X values
+//	expr s xs                    the expression s in x as package expr
+//	                             accepts applied to xs
+//	bigexpr s xs                 like expr but with integer arithmetic
+//	                             that never overflows
+//	chart xs ys options...       a new chart
+//	rows n                       chart option like gochart.NumRows
+//	cols n                       chart option like gochart.NumCols
+//	xformat f                    chart option like gochart.XFormat
+//	yformat f                    chart option like gochart.YFormat
+//	footer stats...              chart option like gochart.Footer with
+//	                             stats named like "sum" or "mean"
+//	chartText c                  c as text like c.WriteTo writes
+//	chartMarkdown c              c as a markdown table
+//	chartHTML c                  c as an HTML table
+//
+// Functions that fail, for example because of an unknown sequence or
+// integer division by zero, stop template execution with an error.
+// FuncMap and HTMLFuncMap return the functions of a zero Funcs.
+package charttemplate
+
+import (
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/expr"
+	"github.com/keep94/gochart/internal/chartspec"
+)
+
+// Funcs configures the chart functions.
+type Funcs struct {
+
+	// The registry for looking up sequences. nil means gochart.Sequences.
+	Sequences *gochart.SequenceRegistry
+
+	// If non nil, expr and bigexpr give up with an error once Done is
+	// closed. Pass the Done channel of a request context to stop
+	// evaluating expressions when the request goes away.
+	Done <-chan struct{}
+}
+
+// FuncMap returns the chart functions for use with text/template.
+func FuncMap() texttemplate.FuncMap {
+	return Funcs{}.Text()
+}
+
+// HTMLFuncMap returns the chart functions for use with html/template.
+func HTMLFuncMap() htmltemplate.FuncMap {
+	return Funcs{}.HTML()
+}
+
+// Text returns the chart functions for use with text/template.
+func (f Funcs) Text() texttemplate.FuncMap {
+	result := f.funcs()
+	result["chartHTML"] = chartHTML
+	return result
+}
+
+// HTML returns the chart functions for use with html/template.
+// chartHTML returns its table as htmltemplate.HTML so that html/template
+// does not escape it again; WriteHTML already escapes every cell. The
+// text that chartText and chartMarkdown return gets escaped as usual.
+func (f Funcs) HTML() htmltemplate.FuncMap {
+	result := f.funcs()
+	result["chartHTML"] = func(c *gochart.Chart) (htmltemplate.HTML, error) {
+		s, err := chartHTML(c)
+		return htmltemplate.HTML(s), err
+	}
+	return result
+}
+
+func (f Funcs) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"ints":   gochart.NewInts,
+		"floats": gochart.NewFloats,
+		"apply": func(name string, xs gochart.Values) (gochart.Values, error) {
+			return chartspec.ApplySequence(f.Sequences, name, xs)
+		},
+		"expr": func(s string, xs gochart.Values) (gochart.Values, error) {
+			return f.applyExpr(s, xs, false)
+		},
+		"bigexpr": func(s string, xs gochart.Values) (gochart.Values, error) {
+			return f.applyExpr(s, xs, true)
+		},
+		"chart":         chart,
+		"rows":          gochart.NumRows,
+		"cols":          gochart.NumCols,
+		"xformat":       gochart.XFormat,
+		"yformat":       gochart.YFormat,
+		"footer":        footer,
+		"chartText":     chartText,
+		"chartMarkdown": chartMarkdown,
+	}
+}
+
+func (f Funcs) applyExpr(
+	s string, xs gochart.Values, bigInts bool) (gochart.Values, error) {
+	e, err := expr.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return chartspec.ApplyExpr(e, xs, bigInts, f.Done)
+}
+
+func chart(
+	xs, ys gochart.Values, options ...gochart.Option) (
+	c *gochart.Chart, err error) {
+	defer chartspec.RecoverPanic(&err)
+	return gochart.NewChart(xs, ys, options...), nil
+}
+
+func footer(names ...string) (gochart.Option, error) {
+	stats := make([]gochart.Stat, len(names))
+	for i, name := range names {
+		stat, err := gochart.ParseStat(name)
+		if err != nil {
+			return nil, err
+		}
+		stats[i] = stat
+	}
+	return gochart.Footer(stats...), nil
+}
+
+func chartText(c *gochart.Chart) (string, error) {
+	return render(c, chartspec.Formats["text"].Write)
+}
+
+func chartMarkdown(c *gochart.Chart) (string, error) {
+	return render(c, chartspec.Formats["markdown"].Write)
+}
+
+func chartHTML(c *gochart.Chart) (string, error) {
+	return render(c, chartspec.Formats["html"].Write)
+}
+
+func render(
+	c *gochart.Chart, write func(c *gochart.Chart, w io.Writer) error) (
+	string, error) {
+	if c == nil {
+		return "", errors.New("charttemplate: nil chart")
+	}
+	var builder strings.Builder
+	if err := write(c, &builder); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
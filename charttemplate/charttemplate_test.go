@@ -0,0 +1,142 @@
+package charttemplate_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/charttemplate"
+)
+
+func TestChartText(t *testing.T) {
+	assertText(
+		t,
+		`{{ $xs := ints 1 1 6 }}`+
+			`{{ chart $xs (apply "partitions" $xs) (cols 2) (footer "sum") | chartText }}`,
+		"+---+--+---+--+\n"+
+			"|  1| 1|  4| 5|\n"+
+			"|  2| 2|  5| 7|\n"+
+			"|  3| 3|  6|11|\n"+
+			"+---+--+---+--+\n"+
+			"|sum|29|   |  |\n"+
+			"+---+--+---+--+\n")
+}
+
+func TestChartMarkdown(t *testing.T) {
+	assertText(
+		t,
+		`{{ $xs := floats 0 0.5 3 }}`+
+			`{{ chart $xs (expr "x*x" $xs) (yformat "%.2f") | chartMarkdown }}`,
+		"| | |\n"+
+			"|--:|--:|\n"+
+			"|0|0.00|\n"+
+			"|0.5|0.25|\n"+
+			"|1|1.00|\n")
+}
+
+func TestChartHTMLEscapedOnce(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("test").
+		Funcs(charttemplate.HTMLFuncMap()).
+		Parse(`{{ $xs := ints 1 1 2 }}` +
+			`{{ chart $xs $xs (yformat "<%d>") | chartHTML }}` +
+			`{{ chart $xs $xs (rows 1) (xformat "<%d>") | chartText }}`))
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "<table>\n" +
+		"<tbody>\n" +
+		"<tr><td>1</td><td>&lt;1&gt;</td></tr>\n" +
+		"<tr><td>2</td><td>&lt;2&gt;</td></tr>\n" +
+		"</tbody>\n" +
+		"</table>\n" +
+		"&#43;---&#43;-&#43;---&#43;-&#43;\n" +
+		"|&lt;1&gt;|1|&lt;2&gt;|2|\n" +
+		"&#43;---&#43;-&#43;---&#43;-&#43;\n"
+	if got := builder.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	assertError(t, `{{ apply "nosuchsequence" (ints 1 1 3) }}`, "nosuchsequence")
+	assertError(t, `{{ apply "primes" (floats 1 1 3) }}`, "integer X values")
+	assertError(t, `{{ expr "1/x" (ints 0 1 3) }}`, "divide by zero")
+	assertError(t, `{{ expr "x +" (ints 0 1 3) }}`, "expr:")
+	assertError(t, `{{ footer "median" }}`, "median")
+	assertError(
+		t, `{{ chart (ints 1 1 3) (ints 1 1 2) | chartText }}`, "same length")
+	assertError(t, `{{ bigexpr "x" (floats 1 1 3) }}`, "integer X values")
+}
+
+func TestBigExpr(t *testing.T) {
+	assertText(
+		t,
+		`{{ $xs := ints 20 1 2 }}{{ chart $xs (bigexpr "x!" $xs) | chartText }}`,
+		"+--+--------------------+\n"+
+			"|20| 2432902008176640000|\n"+
+			"|21|51090942171709440000|\n"+
+			"+--+--------------------+\n")
+}
+
+func TestFuncs(t *testing.T) {
+	registry := gochart.NewSequenceRegistry()
+	registry.Register(
+		"squares",
+		"squares: x^2",
+		func(params []int64) (gochart.Sequence, error) {
+			return gochart.SequenceFunc(func(xs *gochart.Ints) gochart.Values {
+				return xs.Apply(func(x int64) int64 { return x * x })
+			}), nil
+		})
+	done := make(chan struct{})
+	close(done)
+	funcs := charttemplate.Funcs{Sequences: registry, Done: done}
+	tmpl := texttemplate.Must(texttemplate.New("test").
+		Funcs(funcs.Text()).
+		Parse(`{{ $xs := ints 1 1 3 }}{{ chart $xs (apply "squares" $xs) | chartMarkdown }}`))
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := builder.String(), "| | |\n|--:|--:|\n|1|1|\n|2|4|\n|3|9|\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Done is closed, so expressions give up.
+	tmpl = texttemplate.Must(texttemplate.New("test").
+		Funcs(funcs.Text()).Parse(`{{ expr "x" (ints 1 1 3) }}`))
+	err := tmpl.Execute(&strings.Builder{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "canceled") {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+}
+
+func assertText(t *testing.T, text, want string) {
+	t.Helper()
+	tmpl := texttemplate.Must(texttemplate.New("test").
+		Funcs(charttemplate.FuncMap()).Parse(text))
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := builder.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func assertError(t *testing.T, text, substr string) {
+	t.Helper()
+	tmpl := texttemplate.Must(texttemplate.New("test").
+		Funcs(charttemplate.FuncMap()).Parse(text))
+	err := tmpl.Execute(&strings.Builder{}, nil)
+	if err == nil {
+		t.Errorf("%s: expected error", text)
+		return
+	}
+	if !strings.Contains(err.Error(), substr) {
+		t.Errorf("%s: error %q does not contain %q", text, err, substr)
+	}
+}
@@ -0,0 +1,225 @@
+// Package charttest compares charts against golden files in tests.
+//
+// Golden files live in the testdata directory of the package under test.
+// To create or update them, run
+//
+//	go test -update-charts
+package charttest
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+var update = flag.Bool(
+	"update-charts", false, "update chart golden files in testdata")
+
+// Golden compares the text that chart.WriteTo writes against the golden
+// file testdata/name.golden. On a mismatch, Golden reports a cell level
+// diff via t.Errorf. If the -update-charts flag is set, Golden writes the
+// golden file instead.
+func Golden(t testing.TB, name string, chart *gochart.Chart) {
+	t.Helper()
+	var builder strings.Builder
+	if _, err := chart.WriteTo(&builder); err != nil {
+		t.Fatalf("charttest: writing chart %s: %v", name, err)
+		return
+	}
+	GoldenText(t, name, builder.String())
+}
+
+// GoldenText is like Golden except that it compares text that is already
+// rendered.
+func GoldenText(t testing.TB, name, text string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("charttest: %v", err)
+			return
+		}
+		if err := ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatalf("charttest: %v", err)
+			return
+		}
+		t.Logf("charttest: updated %s", path)
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf(
+			"charttest: %s does not exist; run go test -update-charts to create it",
+			path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("charttest: %v", err)
+		return
+	}
+	if diff := Diff(string(want), text); diff != "" {
+		t.Errorf("charttest: %s differs (-want +got):\n%s", path, diff)
+	}
+}
+
+// Diff returns a readable description of the differences between two
+// rendered charts or the empty string if they are the same. If both
+// parse with gochart.ParseChart, Diff compares them cell by cell;
+// otherwise Diff compares them line by line.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantChart, wantErr := gochart.ParseChart(strings.NewReader(want))
+	gotChart, gotErr := gochart.ParseChart(strings.NewReader(got))
+	if wantErr != nil || gotErr != nil {
+		return lineDiff(want, got)
+	}
+	if result := cellDiff(wantChart, gotChart); result != "" {
+		return result
+	}
+
+	// The values match, so the difference is in spacing or rules.
+	return lineDiff(want, got)
+}
+
+func cellDiff(want, got *gochart.ParsedChart) string {
+	var d differ
+	// Row counts change along with the number of values, so only report
+	// them when the number of values is the same.
+	if want.NumCols != got.NumCols ||
+		(want.NumRows != got.NumRows && want.Xs.Len() == got.Xs.Len()) {
+		d.addf(
+			"layout: -%d rows x %d cols +%d rows x %d cols",
+			want.NumRows, want.NumCols, got.NumRows, got.NumCols)
+	}
+	if len(want.Columns) != len(got.Columns) {
+		d.addf(
+			"cells per value: -%d +%d",
+			len(want.Columns)+2, len(got.Columns)+2)
+	}
+	n := want.Xs.Len()
+	if got.Xs.Len() > n {
+		n = got.Xs.Len()
+	}
+	for i := 0; i < n; i++ {
+		wantCells := cellsAt(want, i)
+		gotCells := cellsAt(got, i)
+		switch {
+		case wantCells == nil:
+			d.addf("value %d: + %s", i, strings.Join(gotCells, " | "))
+		case gotCells == nil:
+			d.addf("value %d: - %s", i, strings.Join(wantCells, " | "))
+		default:
+			for j := 0; j < len(wantCells) || j < len(gotCells); j++ {
+				wantCell, gotCell := cellAt(wantCells, j), cellAt(gotCells, j)
+				if wantCell != gotCell {
+					d.addf(
+						"value %d (x %s): %s: -%s +%s",
+						i, wantCells[0], cellName(j), wantCell, gotCell)
+				}
+			}
+		}
+	}
+	for _, stat := range footerStats(want, got) {
+		wantValue, wantOk := want.Footer[stat]
+		gotValue, gotOk := got.Footer[stat]
+		switch {
+		case !wantOk:
+			d.addf("footer %v: +%s", stat, gotValue)
+		case !gotOk:
+			d.addf("footer %v: -%s", stat, wantValue)
+		case wantValue != gotValue:
+			d.addf("footer %v: -%s +%s", stat, wantValue, gotValue)
+		}
+	}
+	return d.String()
+}
+
+// cellsAt returns the cells of the idx value of p or nil if there is no
+// such value.
+func cellsAt(p *gochart.ParsedChart, idx int) []string {
+	if idx >= p.Xs.Len() {
+		return nil
+	}
+	result := []string{p.Xs.Value(idx).(string), p.Ys.Value(idx).(string)}
+	for _, column := range p.Columns {
+		result = append(result, column.Value(idx).(string))
+	}
+	return result
+}
+
+func cellAt(cells []string, idx int) string {
+	if idx < len(cells) {
+		return cells[idx]
+	}
+	return ""
+}
+
+func cellName(idx int) string {
+	switch idx {
+	case 0:
+		return "x"
+	case 1:
+		return "y"
+	default:
+		return fmt.Sprintf("column %d", idx-1)
+	}
+}
+
+// footerStats returns the stats in the footers of want and got in order.
+func footerStats(want, got *gochart.ParsedChart) []gochart.Stat {
+	seen := make(map[gochart.Stat]bool)
+	var result []gochart.Stat
+	for _, footer := range []map[gochart.Stat]string{want.Footer, got.Footer} {
+		for stat := range footer {
+			if !seen[stat] {
+				seen[stat] = true
+				result = append(result, stat)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	var d differ
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		switch {
+		case i >= len(gotLines):
+			d.addf("line %d: -%s", i+1, wantLines[i])
+		case i >= len(wantLines):
+			d.addf("line %d: +%s", i+1, gotLines[i])
+		case wantLines[i] != gotLines[i]:
+			d.addf("line %d: -%s", i+1, wantLines[i])
+			d.addf("line %d: +%s", i+1, gotLines[i])
+		}
+	}
+	if d.String() == "" {
+		return "trailing newline differs\n"
+	}
+	return d.String()
+}
+
+type differ struct {
+	builder strings.Builder
+}
+
+func (d *differ) addf(format string, args ...interface{}) {
+	fmt.Fprintf(&d.builder, format, args...)
+	d.builder.WriteString("\n")
+}
+
+func (d *differ) String() string {
+	return d.builder.String()
+}
@@ -0,0 +1,118 @@
+package charttest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/charttest"
+)
+
+func TestGolden(t *testing.T) {
+	charttest.Golden(t, "squares", squares(9, gochart.Footer(gochart.StatSum)))
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	ft := &fakeT{TB: t}
+	charttest.Golden(ft, "squares", squares(10, gochart.Footer(gochart.StatSum)))
+	if !strings.Contains(ft.errors, "value 9: + 10 | 100") {
+		t.Errorf("Expected added value in:\n%s", ft.errors)
+	}
+	if !strings.Contains(ft.errors, "footer sum: -285 +385") {
+		t.Errorf("Expected footer diff in:\n%s", ft.errors)
+	}
+}
+
+func TestGoldenMissing(t *testing.T) {
+	ft := &fakeT{TB: t}
+	charttest.Golden(ft, "nosuch", squares(3))
+	if !strings.Contains(ft.fatals, "-update-charts") {
+		t.Errorf("Expected hint to update, got %q", ft.fatals)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	want := render(squares(4, gochart.NumCols(2)))
+	got := strings.Replace(want, "| 9|", "|10|", 1)
+	assertEqual(t, "value 2 (x 3): y: -9 +10\n", charttest.Diff(want, got))
+	assertEqual(t, "", charttest.Diff(want, want))
+}
+
+func TestDiffLayout(t *testing.T) {
+	want := render(squares(4, gochart.NumCols(2)))
+	got := render(squares(4))
+	assertEqual(
+		t,
+		"layout: -2 rows x 2 cols +4 rows x 1 cols\n",
+		charttest.Diff(want, got))
+}
+
+func TestDiffMissingValue(t *testing.T) {
+	want := render(squares(3, gochart.Column(labels(3), "%s")))
+	got := render(squares(2, gochart.Column(labels(2), "%s")))
+	assertEqual(t, "value 2: - 3 | 9 | #3\n", charttest.Diff(want, got))
+}
+
+func TestDiffSpacing(t *testing.T) {
+	want := "+-+-+\n|1|1|\n+-+-+\n"
+	got := "+--+-+\n| 1|1|\n+--+-+\n"
+	assertEqual(
+		t,
+		"line 1: -+-+-+\nline 1: ++--+-+\nline 2: -|1|1|\nline 2: +| 1|1|\n"+
+			"line 3: -+-+-+\nline 3: ++--+-+\n",
+		charttest.Diff(want, got))
+}
+
+func TestDiffUnparsable(t *testing.T) {
+	assertEqual(
+		t,
+		"line 2: -b\nline 2: +c\nline 3: +d\n",
+		charttest.Diff("a\nb\n", "a\nc\nd\n"))
+	assertEqual(
+		t,
+		"trailing newline differs\n",
+		charttest.Diff("a\n", "a"))
+}
+
+type fakeT struct {
+	testing.TB
+	errors string
+	fatals string
+}
+
+func (f *fakeT) Helper() {
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors += fmt.Sprintf(format, args...)
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.fatals += fmt.Sprintf(format, args...)
+}
+
+func squares(count int, options ...gochart.Option) *gochart.Chart {
+	xs := gochart.NewInts(1, 1, count)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	return gochart.NewChart(xs, ys, options...)
+}
+
+func labels(count int) gochart.Values {
+	return gochart.Map(gochart.NewInts(1, 1, count), func(x interface{}) interface{} {
+		return fmt.Sprintf("#%d", x)
+	})
+}
+
+func render(chart *gochart.Chart) string {
+	var builder strings.Builder
+	chart.WriteTo(&builder)
+	return builder.String()
+}
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
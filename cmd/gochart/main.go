@@ -0,0 +1,106 @@
+// Command gochart prints a chart of an expression in x or of a named
+// integer sequence.
+//
+// Usage:
+//
+//	gochart [flags] expression
+//	gochart [flags] -seq sequence
+//
+// For example,
+//
+//	gochart -start 0 -step 0.1 -count 20 -cols 2 -ydigits 4 'x^2 - 2'
+//
+// charts x^2 - 2 for x = 0.0, 0.1, ..., 1.9 in two columns. gochart uses
+// integer arithmetic when start and step are integers and floating point
+// arithmetic otherwise or when -float is given. -big uses integer
+// arithmetic that never overflows. -format selects text, csv, markdown,
+// html, json, or svg output.
+//
+// -seq charts a sequence registered in gochart.Sequences such as
+// partitions or ugly(3,5,7) in place of an expression. -list lists the
+// available sequences.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/internal/chartspec"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// errUsage means that run already reported bad usage to stderr.
+var errUsage = errors.New("gochart: bad usage")
+
+// run runs gochart with args writing the chart to stdout and usage
+// messages to stderr.
+func run(args []string, stdout, stderr io.Writer) error {
+	var spec chartspec.Spec
+	flags := flag.NewFlagSet("gochart", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&spec.Start, "start", "1", "first x value")
+	flags.StringVar(&spec.Step, "step", "1", "increment between x values")
+	flags.IntVar(&spec.Count, "count", 10, "number of x values")
+	flags.BoolVar(&spec.Float, "float", false, "use floating point arithmetic")
+	flags.BoolVar(
+		&spec.Big, "big", false, "use integer arithmetic that never overflows")
+	flags.IntVar(&spec.Rows, "rows", 0, "number of rows, 0 means as needed")
+	flags.IntVar(&spec.Cols, "cols", 0, "number of columns, 0 means as needed")
+	flags.IntVar(
+		&spec.XDigits,
+		"xdigits",
+		-1,
+		"fraction digits for floating point x values, -1 means as needed")
+	flags.IntVar(
+		&spec.YDigits,
+		"ydigits",
+		-1,
+		"fraction digits for floating point y values, -1 means as needed")
+	flags.StringVar(
+		&spec.Seq, "seq", "", "chart the named sequence instead of an expression")
+	format := flags.String(
+		"format", "text", "output format: text, csv, markdown, html, json, or svg")
+	list := flags.Bool("list", false, "list the named sequences")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gochart [flags] expression")
+		fmt.Fprintln(stderr, "       gochart [flags] -seq sequence")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *list {
+		for _, name := range gochart.Sequences.Names() {
+			fmt.Fprintln(stdout, gochart.Sequences.Usage(name))
+		}
+		return nil
+	}
+	if (spec.Seq == "" && flags.NArg() != 1) || (spec.Seq != "" && flags.NArg() != 0) {
+		flags.Usage()
+		return errUsage
+	}
+	spec.Expr = flags.Arg(0)
+	f, ok := chartspec.Formats[*format]
+	if !ok {
+		return fmt.Errorf("gochart: unknown format %q", *format)
+	}
+	chart, err := spec.Chart()
+	if err != nil {
+		return err
+	}
+	return f.Write(chart, stdout)
+}
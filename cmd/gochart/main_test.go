@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRunText(t *testing.T) {
+	assertRun(
+		t,
+		[]string{"-count", "6", "-cols", "2", "x^2 - 2"},
+		`+-+--+-+--+
+|1|-1|4|14|
+|2| 2|5|23|
+|3| 7|6|34|
++-+--+-+--+
+`)
+}
+
+func TestRunFloat(t *testing.T) {
+	assertRun(
+		t,
+		[]string{
+			"-start", "0", "-step", "0.5", "-count", "3",
+			"-xdigits", "1", "-ydigits", "3", "-format", "csv", "x/3"},
+		`0.0,0.000
+0.5,0.167
+1.0,0.333
+`)
+}
+
+func TestRunDigitsIgnoredForIntegers(t *testing.T) {
+	assertRun(
+		t,
+		[]string{
+			"-count", "4", "-xdigits", "1", "-ydigits", "2", "-format", "csv",
+			"x/3"},
+		`1,0
+2,0
+3,1
+4,1
+`)
+}
+
+func TestRunForceFloat(t *testing.T) {
+	assertRun(
+		t,
+		[]string{"-float", "-count", "2", "-format", "csv", "x/2"},
+		`1,0.5
+2,1
+`)
+}
+
+func TestRunBig(t *testing.T) {
+	assertRun(
+		t,
+		[]string{"-start", "20", "-count", "2", "-big", "-format", "csv", "x!"},
+		`20,2432902008176640000
+21,51090942171709440000
+`)
+}
+
+func TestRunSequence(t *testing.T) {
+	assertRun(
+		t,
+		[]string{"-seq", "ugly(3, 5, 7)", "-count", "6", "-cols", "2"},
+		`+-+--+-+--+
+|1| 1|4| 7|
+|2| 3|5| 9|
+|3| 5|6|15|
++-+--+-+--+
+`)
+}
+
+func TestRunList(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-list"}, &out, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "\npartitions: ") {
+		t.Errorf("Expected partitions in:\n%s", out.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	assertRunError(t, "-count", "3", "x +")
+	assertRunError(t, "-format", "pdf", "x")
+	assertRunError(t, "-start", "0", "1/x")
+	assertRunError(t, "-start", "abc", "x")
+	assertRunError(t, "x", "x")
+	assertRunError(t, "-big", "-start", "0.5", "x")
+	assertRunError(t, "-big", "-float", "x")
+	assertRunError(t, "-start", "-3", "x!")
+	assertRunError(t, "-seq", "nosuch")
+	assertRunError(t, "-seq", "primes", "x")
+	assertRunError(t, "-seq", "primes", "-start", "0")
+	assertRunError(t, "-seq", "primes", "-start", "1.5")
+}
+
+func assertRun(t *testing.T, args []string, expected string) {
+	t.Helper()
+	var out bytes.Buffer
+	if err := run(args, &out, ioutil.Discard); err != nil {
+		t.Fatalf("run(%s) failed: %v", strings.Join(args, " "), err)
+	}
+	if actual := out.String(); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func assertRunError(t *testing.T, args ...string) {
+	t.Helper()
+	if err := run(args, ioutil.Discard, ioutil.Discard); err == nil {
+		t.Errorf("Expected error for run(%s)", strings.Join(args, " "))
+	}
+}
@@ -0,0 +1,184 @@
+package gochart
+
+// CompareValues returns a chart comparing old Y values against new Y
+// values by X value. oldXs and oldYs must be the same length as must
+// newXs and newYs or else CompareValues panics. X values match when they
+// format the same way according to XFormat.
+//
+// Each row of the returned chart shows an X value followed by its old Y
+// value, its new Y value, and the new Y value minus the old Y value. The
+// old or new Y value is blank if the X value appears only in the other
+// set of values, and the difference is blank unless both Y values are
+// int64, float64, *big.Int, or *big.Rat values. X values appear in old
+// order with X values found only in new values placed after the X value
+// that precedes them in new values.
+//
+// options are the options for the returned chart. OnlyDifferences and
+// ContextLines limit the chart to the X values whose Y values differ.
+// Numeric Y values are the same if they are numerically equal; other Y
+// values are the same if they format the same way. Any Column options add
+// columns after the difference column. A Footer shows statistics of the
+// old Y values, skipping X values found only in new values.
+func CompareValues(
+	oldXs, oldYs, newXs, newYs Values, options ...Option) *Chart {
+	if oldXs.Len() != oldYs.Len() || newXs.Len() != newYs.Len() {
+		panic("xs and ys must have same length")
+	}
+	settings := &settingsType{xFormat: "%v", yFormat: "%v", context: -1}
+	Options(options).mutate(settings)
+	rows := matchByX(oldXs, oldYs, newXs, newYs, settings)
+	for i := range rows {
+		rows[i].changed = rows[i].isChanged(settings)
+	}
+	rows, hunkStarts := keepChanges(rows, settings.context)
+	xs := make(valueSlice, len(rows))
+	olds := make(valueSlice, len(rows))
+	news := make(valueSlice, len(rows))
+	deltas := make(valueSlice, len(rows))
+	for i, row := range rows {
+		xs[i] = row.x
+		olds[i] = row.oldY
+		news[i] = row.newY
+		deltas[i] = row.delta()
+	}
+	var ruleXs []interface{}
+	for _, idx := range hunkStarts {
+		ruleXs = append(ruleXs, xs[idx])
+	}
+	return NewChart(
+		xs,
+		olds,
+		Column(news, settings.yFormat),
+		Column(deltas, settings.yFormat),
+		RulesAt(ruleXs...),
+		Options(options))
+}
+
+// CompareCharts is like CompareValues except that it compares the X and Y
+// values of the old chart, from, against those of the new chart, to. The
+// returned chart formats values like from does unless options say
+// otherwise. CompareCharts ignores any Column values of from and to.
+func CompareCharts(from, to *Chart, options ...Option) *Chart {
+	return CompareValues(
+		from.xs,
+		from.ys,
+		to.xs,
+		to.ys,
+		from.settings.formatOptions(),
+		Options(options))
+}
+
+// OnlyDifferences limits a chart from CompareValues or CompareCharts to
+// the X values whose Y values differ. Horizontal rules separate X values
+// that are not consecutive. OnlyDifferences is the same as
+// ContextLines(0).
+func OnlyDifferences() Option {
+	return ContextLines(0)
+}
+
+// ContextLines limits a chart from CompareValues or CompareCharts to the
+// X values whose Y values differ along with up to n X values before and
+// after each one, like the context lines of a unified diff. Horizontal
+// rules separate groups of X values that are not consecutive.
+func ContextLines(n int) Option {
+	return optionFunc(func(s *settingsType) {
+		s.context = n
+	})
+}
+
+// missingType marks a Y value that is missing from a comparison. It
+// formats as the empty string.
+type missingType struct{}
+
+var missing = missingType{}
+
+type compareRow struct {
+	x       interface{}
+	oldY    interface{}
+	newY    interface{}
+	changed bool
+}
+
+func (r *compareRow) isChanged(s *settingsType) bool {
+	if r.oldY == missing || r.newY == missing {
+		return true
+	}
+	if isNumber(r.oldY) && isNumber(r.newY) {
+		return compareValues(r.oldY, r.newY) != 0
+	}
+	return s.format(s.yFormat, r.oldY) != s.format(s.yFormat, r.newY)
+}
+
+func (r *compareRow) delta() interface{} {
+	if isNumber(r.oldY) && isNumber(r.newY) {
+		return subValues(r.newY, r.oldY)
+	}
+	return missing
+}
+
+// matchByX pairs old and new Y values by X value.
+func matchByX(
+	oldXs, oldYs, newXs, newYs Values, s *settingsType) []compareRow {
+	oldIndexes := make(map[string]int, oldXs.Len())
+	for i := 0; i < oldXs.Len(); i++ {
+		oldIndexes[s.format(s.xFormat, oldXs.Value(i))] = i
+	}
+
+	// newOnly[i+1] holds the new only rows that go after old row i.
+	newOnly := make([][]compareRow, oldXs.Len()+1)
+	newYsByOld := make([]interface{}, oldXs.Len())
+	for i := range newYsByOld {
+		newYsByOld[i] = missing
+	}
+	lastOld := -1
+	for i := 0; i < newXs.Len(); i++ {
+		x := newXs.Value(i)
+		if idx, ok := oldIndexes[s.format(s.xFormat, x)]; ok {
+			newYsByOld[idx] = newYs.Value(i)
+			lastOld = idx
+		} else {
+			newOnly[lastOld+1] = append(
+				newOnly[lastOld+1],
+				compareRow{x: x, oldY: missing, newY: newYs.Value(i)})
+		}
+	}
+	result := append([]compareRow(nil), newOnly[0]...)
+	for i := 0; i < oldXs.Len(); i++ {
+		result = append(
+			result,
+			compareRow{x: oldXs.Value(i), oldY: oldYs.Value(i), newY: newYsByOld[i]})
+		result = append(result, newOnly[i+1]...)
+	}
+	return result
+}
+
+// keepChanges returns the changed rows along with context rows before and
+// after each one. If context is negative, keepChanges returns all rows.
+// keepChanges also returns the index of the first returned row of each
+// group of consecutive rows after the first group.
+func keepChanges(rows []compareRow, context int) ([]compareRow, []int) {
+	if context < 0 {
+		return rows, nil
+	}
+	keep := make([]bool, len(rows))
+	for i := range rows {
+		if !rows[i].changed {
+			continue
+		}
+		for j := maxInt(0, i-context); j <= i+context && j < len(rows); j++ {
+			keep[j] = true
+		}
+	}
+	var result []compareRow
+	var groupStarts []int
+	for i := range rows {
+		if !keep[i] {
+			continue
+		}
+		if len(result) > 0 && !keep[i-1] {
+			groupStarts = append(groupStarts, len(result))
+		}
+		result = append(result, rows[i])
+	}
+	return result, groupStarts
+}
@@ -0,0 +1,165 @@
+package gochart_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestCompareValues(t *testing.T) {
+	oldXs := gochart.NewInts(1, 1, 5)
+	oldYs := oldXs.Apply(func(x int64) int64 { return x * x })
+	newXs := gochart.NewInts(2, 1, 5)
+	newYs := newXs.Apply(func(x int64) int64 {
+		if x == 4 {
+			return 17
+		}
+		return x * x
+	})
+	assertChart(
+		t,
+		gochart.CompareValues(oldXs, oldYs, newXs, newYs),
+		"+-+--+--+-+",
+		"|1| 1|  | |",
+		"|2| 4| 4|0|",
+		"|3| 9| 9|0|",
+		"|4|16|17|1|",
+		"|5|25|25|0|",
+		"|6|  |36| |",
+		"+-+--+--+-+",
+	)
+	assertChart(
+		t,
+		gochart.CompareValues(
+			oldXs, oldYs, newXs, newYs, gochart.OnlyDifferences()),
+		"+-+--+--+-+",
+		"|1| 1|  | |",
+		"+-+--+--+-+",
+		"|4|16|17|1|",
+		"+-+--+--+-+",
+		"|6|  |36| |",
+		"+-+--+--+-+",
+	)
+}
+
+func TestCompareValuesFooterAndColumn(t *testing.T) {
+	oldXs := gochart.NewInts(1, 1, 3)
+	oldYs := oldXs.Apply(func(x int64) int64 { return x * x })
+	newXs := gochart.NewInts(2, 1, 3)
+	newYs := newXs.Apply(func(x int64) int64 { return x * x })
+	notes := gochart.NewInts(10, 10, 4)
+	assertChart(
+		t,
+		gochart.CompareValues(
+			oldXs, oldYs, newXs, newYs,
+			gochart.Footer(gochart.StatCount, gochart.StatSum),
+			gochart.Column(notes, "n%d")),
+		"+-----+--+--+-+---+",
+		"|    1| 1|  | |n10|",
+		"|    2| 4| 4|0|n20|",
+		"|    3| 9| 9|0|n30|",
+		"|    4|  |16| |n40|",
+		"+-----+--+--+-+---+",
+		"|count| 3|  | |   |",
+		"|  sum|14|  | |   |",
+		"+-----+--+--+-+---+",
+	)
+}
+
+func TestCompareValuesContext(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 8)
+	oldYs := xs.Apply(func(x int64) int64 { return x })
+	newYs := xs.Apply(func(x int64) int64 {
+		if x == 2 || x == 7 {
+			return -x
+		}
+		return x
+	})
+	assertChart(
+		t,
+		gochart.CompareValues(xs, oldYs, xs, newYs, gochart.ContextLines(1)),
+		"+-+-+--+---+",
+		"|1|1| 1|  0|",
+		"|2|2|-2| -4|",
+		"|3|3| 3|  0|",
+		"+-+-+--+---+",
+		"|6|6| 6|  0|",
+		"|7|7|-7|-14|",
+		"|8|8| 8|  0|",
+		"+-+-+--+---+",
+	)
+}
+
+func TestCompareValuesOrder(t *testing.T) {
+	oldXs := gochart.NewInts(1, 2, 3)
+	newXs := gochart.NewInts(0, 1, 6)
+	assertChart(
+		t,
+		gochart.CompareValues(
+			oldXs, oldXs, newXs, newXs, gochart.OnlyDifferences()),
+		"+-++-++",
+		"|0||0||",
+		"+-++-++",
+		"|2||2||",
+		"+-++-++",
+		"|4||4||",
+		"+-++-++",
+	)
+}
+
+func TestCompareValuesMixedTypes(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	oldYs := xs.ApplyRat(func(x int64, result *big.Rat) *big.Rat {
+		return result.SetFrac64(x, 2)
+	})
+	newYs := gochart.Map(xs, func(x interface{}) interface{} {
+		if x.(int64) == 3 {
+			return "n/a"
+		}
+		return float64(x.(int64)) / 2
+	})
+	assertChart(
+		t,
+		gochart.CompareValues(
+			xs, oldYs, xs, newYs, gochart.OnlyDifferences(), gochart.RatDecimal(1)),
+		"+-+---+---++",
+		"|3|1.5|n/a||",
+		"+-+---+---++",
+	)
+}
+
+func TestCompareCharts(t *testing.T) {
+	xs := gochart.NewFloats(0, 0.5, 3)
+	from := gochart.NewChart(
+		xs, xs.Apply(func(x float64) float64 { return x * x }),
+		gochart.FractionDigits(1, 3))
+	to := gochart.NewChart(
+		xs, xs.Apply(func(x float64) float64 { return x*x + 0.01 }))
+	chart := gochart.CompareCharts(from, to, gochart.NumCols(1))
+	assertChart(
+		t,
+		chart,
+		"+---+-----+-----+-----+",
+		"|0.0|0.000|0.010|0.010|",
+		"|0.5|0.250|0.260|0.010|",
+		"|1.0|1.000|1.010|0.010|",
+		"+---+-----+-----+-----+",
+	)
+	assertJSONRoundTrip(t, gochart.CompareCharts(
+		gochart.NewChart(xs, xs), to, gochart.OnlyDifferences()))
+	if _, err := json.Marshal(chart); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompareValuesPanics(t *testing.T) {
+	assertPanic(t, func() {
+		gochart.CompareValues(
+			gochart.NewInts(1, 1, 2),
+			gochart.NewInts(1, 1, 3),
+			gochart.NewInts(1, 1, 2),
+			gochart.NewInts(1, 1, 2))
+	})
+}
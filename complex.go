@@ -0,0 +1,56 @@
+package gochart
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// ApplyComplex applies fn to each of these X values and returns the
+// resulting complex Y values. By default, complex128 values show like
+// (1+2i); use ComplexRect, ComplexPolar, or SplitComplex to change this.
+func (f *Floats) ApplyComplex(fn func(float64) complex128) Values {
+	result := make(valueSlice, f.count)
+	for i := 0; i < f.count; i++ {
+		result[i] = fn(f.value(i))
+	}
+	return result
+}
+
+// ComplexRect shows complex128 values in rectangular form like
+// 1.50-0.25i with digits digits after the decimal point in both parts.
+// ComplexRect overrides XFormat and YFormat for complex128 values.
+func ComplexRect(digits int) Option {
+	spec := formatSpec{Kind: "rect", Digits: digits}
+	return complexFormat(spec, func(c complex128) string {
+		return fmt.Sprintf("%.*f%+.*fi", digits, real(c), digits, imag(c))
+	})
+}
+
+// ComplexPolar shows complex128 values in polar form like 2.00∠-1.57 where
+// the first number is the magnitude and the second is the angle in radians
+// between -π and π. Both numbers have digits digits after the decimal
+// point. ComplexPolar overrides XFormat and YFormat for complex128 values.
+func ComplexPolar(digits int) Option {
+	spec := formatSpec{Kind: "polar", Digits: digits}
+	return complexFormat(spec, func(c complex128) string {
+		return fmt.Sprintf(
+			"%.*f∠%.*f", digits, cmplx.Abs(c), digits, cmplx.Phase(c))
+	})
+}
+
+// SplitComplex shows the real and imaginary parts of complex128 Y values
+// in separate Y columns. Both parts are float64 values formatted with
+// YFormat. Y values that are not complex128 go in the first of the two
+// Y columns.
+func SplitComplex() Option {
+	return optionFunc(func(s *settingsType) {
+		s.splitComplex = true
+	})
+}
+
+func complexFormat(spec formatSpec, f func(c complex128) string) Option {
+	return optionFunc(func(s *settingsType) {
+		s.complexFormat = f
+		s.complexFormatSpec = spec
+	})
+}
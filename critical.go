@@ -0,0 +1,185 @@
+package gochart
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	kMaxIterations = 200
+	kEpsilon       = 2.220446049250313e-16
+	kInvPhi        = 0.6180339887498949
+)
+
+// Kinds of points that FindRoots, FindExtrema, and FindCritical return.
+// These are the annotations that appear in the notes Values.
+const (
+	NoteRoot = "root"
+	NoteMin  = "min"
+	NoteMax  = "max"
+)
+
+// FindRoots scans consecutive pairs of these X values for places where fn
+// is zero or changes sign and refines each one with Brent's method. fn
+// must be continuous. FindRoots returns the X value of each root, fn at
+// each root, and a note that says NoteRoot for each root. The returned
+// Values are ready for NewChart along with Column(notes, "%s"). Roots
+// closer together than the spacing of these X values may be missed.
+func (f *Floats) FindRoots(
+	fn func(float64) float64) (xs, ys, notes Values) {
+	return f.find(fn, true, false)
+}
+
+// FindExtrema scans consecutive triples of these X values for local minima
+// and maxima of fn and refines each one with golden section search. It
+// returns the X value of each extremum, fn at each extremum, and a note
+// that says NoteMin or NoteMax like FindRoots does. Extrema at the first
+// or last X value are not reported.
+func (f *Floats) FindExtrema(
+	fn func(float64) float64) (xs, ys, notes Values) {
+	return f.find(fn, false, true)
+}
+
+// FindCritical returns the roots and extrema of fn together, ordered by X
+// value. See FindRoots and FindExtrema.
+func (f *Floats) FindCritical(
+	fn func(float64) float64) (xs, ys, notes Values) {
+	return f.find(fn, true, true)
+}
+
+type criticalPoint struct {
+	x    float64
+	note string
+}
+
+func (f *Floats) find(
+	fn func(float64) float64, roots, extrema bool) (xs, ys, notes Values) {
+	xvals := make([]float64, f.count)
+	yvals := make([]float64, f.count)
+	for i := range xvals {
+		xvals[i] = f.value(i)
+		yvals[i] = fn(xvals[i])
+	}
+	negFn := func(x float64) float64 { return -fn(x) }
+	var points []criticalPoint
+	for i := range xvals {
+		x, y := xvals[i], yvals[i]
+		if roots {
+			if y == 0 {
+				points = append(points, criticalPoint{x: x, note: NoteRoot})
+			} else if i > 0 && yvals[i-1] != 0 && (yvals[i-1] < 0) != (y < 0) {
+				root := findRoot(fn, xvals[i-1], x, yvals[i-1], y)
+				points = append(points, criticalPoint{x: root, note: NoteRoot})
+			}
+		}
+		if extrema && i > 0 && i < len(xvals)-1 {
+			prevY, nextY := yvals[i-1], yvals[i+1]
+			if y < prevY && y <= nextY {
+				minX := findMin(fn, xvals[i-1], xvals[i+1])
+				points = append(points, criticalPoint{x: minX, note: NoteMin})
+			} else if y > prevY && y >= nextY {
+				maxX := findMin(negFn, xvals[i-1], xvals[i+1])
+				points = append(points, criticalPoint{x: maxX, note: NoteMax})
+			}
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].x < points[j].x
+	})
+	resultXs := make(valueSlice, len(points))
+	resultYs := make(valueSlice, len(points))
+	resultNotes := make(valueSlice, len(points))
+	for i, p := range points {
+		resultXs[i] = p.x
+		resultYs[i] = fn(p.x)
+		resultNotes[i] = p.note
+	}
+	return resultXs, resultYs, resultNotes
+}
+
+// findRoot returns x between a and b such that fn(x) = 0 using Brent's
+// method. fa = fn(a) and fb = fn(b) must have opposite signs.
+func findRoot(fn func(float64) float64, a, b, fa, fb float64) float64 {
+	c, fc := b, fb
+	var d, e float64
+	for i := 0; i < kMaxIterations; i++ {
+		if (fb > 0) == (fc > 0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2.0*kEpsilon*math.Abs(b) + math.SmallestNonzeroFloat64
+		m := 0.5 * (c - b)
+		if math.Abs(m) <= tol || fb == 0 {
+			return b
+		}
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// Try inverse quadratic interpolation or the secant method.
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2.0 * m * s
+				q = 1.0 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2.0*m*q*(q-r) - (b-a)*(r-1.0))
+				q = (q - 1.0) * (r - 1.0) * (s - 1.0)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2.0*p < math.Min(3.0*m*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = d
+			}
+		} else {
+			// Bisect
+			d = m
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else if m > 0 {
+			b += tol
+		} else {
+			b -= tol
+		}
+		fb = fn(b)
+	}
+	return b
+}
+
+// findMin returns x between a and b where fn has a local minimum using
+// golden section search. fn must have exactly one local minimum between
+// a and b.
+func findMin(fn func(float64) float64, a, b float64) float64 {
+	x1 := b - kInvPhi*(b-a)
+	x2 := a + kInvPhi*(b-a)
+	f1, f2 := fn(x1), fn(x2)
+	for i := 0; i < kMaxIterations; i++ {
+		if b-a <= 2.0*kEpsilon*(math.Abs(x1)+math.Abs(x2)) {
+			break
+		}
+		if f1 < f2 {
+			b, x2, f2 = x2, x1, f1
+			x1 = b - kInvPhi*(b-a)
+			f1 = fn(x1)
+		} else {
+			a, x1, f1 = x1, x2, f2
+			x2 = a + kInvPhi*(b-a)
+			f2 = fn(x2)
+		}
+	}
+	return (a + b) / 2.0
+}
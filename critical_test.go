@@ -0,0 +1,52 @@
+package gochart_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestFindRoots(t *testing.T) {
+	xs, ys, notes := gochart.NewFloats(0.5, 1.0, 10).FindRoots(math.Sin)
+	assertEqual(t, 3, xs.Len())
+	for i := 0; i < xs.Len(); i++ {
+		assertCloseTo(t, float64(i+1)*math.Pi, xs.Value(i).(float64))
+		if math.Abs(ys.Value(i).(float64)) > 1e-12 {
+			t.Errorf("Expected root, got %v", ys.Value(i))
+		}
+		assertEqual(t, gochart.NoteRoot, notes.Value(i))
+	}
+}
+
+func TestFindRootsExact(t *testing.T) {
+	xs, _, _ := gochart.NewFloats(-2.0, 1.0, 5).FindRoots(
+		func(x float64) float64 { return x })
+	assertValuesEqual(t, xs, 0.0)
+}
+
+func TestFindExtrema(t *testing.T) {
+	xs, ys, notes := gochart.NewFloats(0.3, 0.5, 14).FindExtrema(math.Cos)
+	assertEqual(t, 2, xs.Len())
+	assertCloseTo(t, math.Pi, xs.Value(0).(float64))
+	assertCloseTo(t, -1.0, ys.Value(0).(float64))
+	assertEqual(t, gochart.NoteMin, notes.Value(0))
+	assertCloseTo(t, 2.0*math.Pi, xs.Value(1).(float64))
+	assertCloseTo(t, 1.0, ys.Value(1).(float64))
+	assertEqual(t, gochart.NoteMax, notes.Value(1))
+}
+
+func TestFindCriticalNone(t *testing.T) {
+	xs, ys, notes := gochart.NewFloats(1.0, 1.0, 10).FindCritical(math.Exp)
+	assertEqual(t, 0, xs.Len())
+	assertEqual(t, 0, ys.Len())
+	assertEqual(t, 0, notes.Len())
+}
+
+func TestColumnPanic(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	assertPanic(t, func() {
+		gochart.NewChart(
+			xs, xs, gochart.Column(gochart.NewInts(1, 1, 2), "%d"))
+	})
+}
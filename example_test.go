@@ -3,7 +3,10 @@ package gochart_test
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"math/cmplx"
 	"strings"
+	"time"
 
 	"github.com/keep94/gochart"
 	"github.com/keep94/gomath"
@@ -315,7 +318,7 @@ func ExampleChart_WriteTo() {
 	// +----+------+----+------+----+------+----+------+
 }
 
-func ExampleSquare() {
+func Example_square() {
 	xs := gochart.NewInts(1, 1, 10)
 	ys := xs.Apply(func(x int64) int64 { return x * x })
 	var builder strings.Builder
@@ -337,3 +340,176 @@ func ExampleSquare() {
 	// |10|100|
 	// +--+---+
 }
+
+func ExampleWrapWidth() {
+	xs := gochart.NewInts(18, 2, 4)
+	ys := xs.ApplyBigInt(
+		func(x int64, result *big.Int) *big.Int {
+			return result.MulRange(1, x)
+		})
+	gochart.NewChart(xs, ys, gochart.WrapWidth(10)).WriteTo(nil)
+	// Output:
+	// +--+----------+
+	// |18|    640237|
+	// |  |3705728000|
+	// |20| 243290200|
+	// |  |8176640000|
+	// |22|        11|
+	// |  |2400072777|
+	// |  |7607680000|
+	// |24|      6204|
+	// |  |4840173323|
+	// |  |9439360000|
+	// +--+----------+
+}
+
+func ExampleTranspose() {
+	xs := gochart.NewInts(1, 1, 20)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	gochart.NewChart(xs, ys, gochart.Transpose(40)).WriteTo(nil)
+	// Output:
+	// +-+-+-+--+--+--+--+--+--+---+---+---+
+	// |1|2|3| 4| 5| 6| 7| 8| 9| 10| 11| 12|
+	// |1|4|9|16|25|36|49|64|81|100|121|144|
+	// +-+-+-+--+--+--+--+--+--+---+---+---+
+	//
+	// +---+---+---+---+---+---+---+---+
+	// | 13| 14| 15| 16| 17| 18| 19| 20|
+	// |169|196|225|256|289|324|361|400|
+	// +---+---+---+---+---+---+---+---+
+}
+
+func ExampleNewGeometricInts() {
+	xs := gochart.NewGeometricInts(1, 2, 7)
+	ys := xs.ApplyBigIntStream(gomath.Fibonacci(1, 1))
+	gochart.NewChart(xs, ys).WriteTo(nil)
+	// Output:
+	// +--+--------------+
+	// | 1|             1|
+	// | 2|             1|
+	// | 4|             3|
+	// | 8|            21|
+	// |16|           987|
+	// |32|       2178309|
+	// |64|10610209857723|
+	// +--+--------------+
+}
+
+func ExampleInts_ApplyRat() {
+	// Harmonic numbers
+	h := new(big.Rat)
+	xs := gochart.NewInts(1, 1, 8)
+	ys := xs.ApplyRat(func(x int64, result *big.Rat) *big.Rat {
+		h.Add(h, big.NewRat(1, x))
+		return result.Set(h)
+	})
+	gochart.NewChart(xs, ys, gochart.RatMixed()).WriteTo(nil)
+	// Output:
+	// +-+---------+
+	// |1|        1|
+	// |2|    1 1/2|
+	// |3|    1 5/6|
+	// |4|   2 1/12|
+	// |5|  2 17/60|
+	// |6|   2 9/20|
+	// |7| 2 83/140|
+	// |8|2 201/280|
+	// +-+---------+
+}
+
+func ExampleSplitComplex() {
+	// The 8th roots of unity
+	xs := gochart.NewFloats(0, 1, 8)
+	ys := xs.ApplyComplex(func(k float64) complex128 {
+		return cmplx.Exp(complex(0, 2*math.Pi*k/8))
+	})
+	gochart.NewChart(
+		xs, ys, gochart.SplitComplex(), gochart.YFormat("%.4f")).WriteTo(nil)
+	// Output:
+	// +-+-------+-------+
+	// |0| 1.0000| 0.0000|
+	// |1| 0.7071| 0.7071|
+	// |2| 0.0000| 1.0000|
+	// |3|-0.7071| 0.7071|
+	// |4|-1.0000| 0.0000|
+	// |5|-0.7071|-0.7071|
+	// |6|-0.0000|-1.0000|
+	// |7| 0.7071|-0.7071|
+	// +-+-------+-------+
+}
+
+func ExampleNewTimes() {
+	// Balance of a $1000 loan at 12% a year paid off at $100 a month
+	balance := 1000.0
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	xs := gochart.NewTimes(start, gochart.EveryDate(0, 1, 0), 6)
+	ys := xs.Apply(func(t time.Time) float64 {
+		result := balance
+		balance = balance*1.01 - 100.0
+		return result
+	})
+	gochart.NewChart(
+		xs,
+		ys,
+		gochart.TimeLayout("Jan 2006"),
+		gochart.YFormat("%.2f")).WriteTo(nil)
+	// Output:
+	// +--------+-------+
+	// |Jan 2024|1000.00|
+	// |Feb 2024| 910.00|
+	// |Mar 2024| 819.10|
+	// |Apr 2024| 727.29|
+	// |May 2024| 634.56|
+	// |Jun 2024| 540.91|
+	// +--------+-------+
+}
+
+func ExampleDiff() {
+	// Gaps between consecutive primes
+	xs := gochart.NewInts(1, 1, 10)
+	primes := xs.ApplyStream(gomath.Primes(2))
+	gochart.NewChart(xs, gochart.Diff(primes), gochart.Transpose(80)).WriteTo(nil)
+	// Output:
+	// +-+-+-+-+-+-+-+-+-+--+
+	// |1|2|3|4|5|6|7|8|9|10|
+	// |2|1|2|2|4|2|4|2|4| 6|
+	// +-+-+-+-+-+-+-+-+-+--+
+}
+
+func ExampleFloats_FindCritical() {
+	xs, ys, notes := gochart.NewFloats(-3.0, 0.5, 13).FindCritical(func(x float64) float64 {
+		return x*x*x - 3*x
+	})
+	gochart.NewChart(
+		xs,
+		ys,
+		gochart.FractionDigits(6, 6),
+		gochart.Column(notes, "%s")).WriteTo(nil)
+	// Output:
+	// +---------+---------+----+
+	// |-1.732051| 0.000000|root|
+	// |-1.000000| 2.000000| max|
+	// | 0.000000| 0.000000|root|
+	// | 1.000000|-2.000000| min|
+	// | 1.732051|-0.000000|root|
+	// +---------+---------+----+
+}
+
+func ExampleCompareValues() {
+	xs := gochart.NewInts(1, 1, 10)
+	oldYs := xs.Apply(func(x int64) int64 { return x * x })
+	newYs := xs.Apply(func(x int64) int64 {
+		if x == 7 {
+			return 50
+		}
+		return x * x
+	})
+	gochart.CompareValues(
+		xs, oldYs, xs, newYs, gochart.ContextLines(1)).WriteTo(nil)
+	// Output:
+	// +-+--+--+-+
+	// |6|36|36|0|
+	// |7|49|50|1|
+	// |8|64|64|0|
+	// +-+--+--+-+
+}
@@ -0,0 +1,374 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// node is a node of a compiled expression. evalBig must not modify x or
+// return a value that it or any other node later modifies.
+type node interface {
+	evalFloat(x float64) float64
+	evalInt(x int64) int64
+	evalBig(x *big.Int) *big.Int
+}
+
+type varNode struct{}
+
+func (varNode) evalFloat(x float64) float64 { return x }
+func (varNode) evalInt(x int64) int64       { return x }
+func (varNode) evalBig(x *big.Int) *big.Int { return x }
+
+type numNode struct {
+	f float64
+	i int64
+	b *big.Int
+}
+
+func newNumNode(s string) (node, error) {
+	if b, ok := new(big.Int).SetString(s, 10); ok {
+		f, _ := new(big.Float).SetInt(b).Float64()
+		return numNode{f: f, i: b.Int64(), b: b}, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expr: bad number %q", s)
+	}
+	return newConstNode(f), nil
+}
+
+// newConstNode returns a node for the constant f. Integer arithmetic
+// truncates f toward zero.
+func newConstNode(f float64) numNode {
+	return numNode{f: f, i: int64(f), b: floatToBig(f)}
+}
+
+func (n numNode) evalFloat(x float64) float64 { return n.f }
+func (n numNode) evalInt(x int64) int64       { return n.i }
+func (n numNode) evalBig(x *big.Int) *big.Int { return n.b }
+
+type negNode struct {
+	operand node
+}
+
+func (n negNode) evalFloat(x float64) float64 { return -n.operand.evalFloat(x) }
+func (n negNode) evalInt(x int64) int64       { return -n.operand.evalInt(x) }
+
+func (n negNode) evalBig(x *big.Int) *big.Int {
+	return new(big.Int).Neg(n.operand.evalBig(x))
+}
+
+type factNode struct {
+	operand node
+}
+
+func (n factNode) evalFloat(x float64) float64 {
+	return factFloat(n.operand.evalFloat(x))
+}
+
+func (n factNode) evalInt(x int64) int64 {
+	return factInt(n.operand.evalInt(x))
+}
+
+func (n factNode) evalBig(x *big.Int) *big.Int {
+	return factBig(n.operand.evalBig(x))
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n binaryNode) evalFloat(x float64) float64 {
+	a, b := n.left.evalFloat(x), n.right.evalFloat(x)
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		return a / b
+	case "%":
+		return math.Mod(a, b)
+	case "^":
+		return math.Pow(a, b)
+	default:
+		return boolFloat(compare(n.op, compareFloat(a, b)))
+	}
+}
+
+func (n binaryNode) evalInt(x int64) int64 {
+	a, b := n.left.evalInt(x), n.right.evalInt(x)
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		return a / b
+	case "%":
+		return a % b
+	case "^":
+		return powInt(a, b)
+	default:
+		return boolInt(compare(n.op, compareInt(a, b)))
+	}
+}
+
+func (n binaryNode) evalBig(x *big.Int) *big.Int {
+	a, b := n.left.evalBig(x), n.right.evalBig(x)
+	switch n.op {
+	case "+":
+		return new(big.Int).Add(a, b)
+	case "-":
+		return new(big.Int).Sub(a, b)
+	case "*":
+		return new(big.Int).Mul(a, b)
+	case "/":
+		return new(big.Int).Quo(a, b)
+	case "%":
+		return new(big.Int).Rem(a, b)
+	case "^":
+		return powBig(a, b)
+	default:
+		return boolBig(compare(n.op, a.Cmp(b)))
+	}
+}
+
+// logicalNode evaluates && and || evaluating the right side only when
+// needed.
+type logicalNode struct {
+	and   bool
+	left  node
+	right node
+}
+
+func (n logicalNode) evalFloat(x float64) float64 {
+	if (n.left.evalFloat(x) != 0) != n.and {
+		return boolFloat(!n.and)
+	}
+	return boolFloat(n.right.evalFloat(x) != 0)
+}
+
+func (n logicalNode) evalInt(x int64) int64 {
+	if (n.left.evalInt(x) != 0) != n.and {
+		return boolInt(!n.and)
+	}
+	return boolInt(n.right.evalInt(x) != 0)
+}
+
+func (n logicalNode) evalBig(x *big.Int) *big.Int {
+	if (n.left.evalBig(x).Sign() != 0) != n.and {
+		return boolBig(!n.and)
+	}
+	return boolBig(n.right.evalBig(x).Sign() != 0)
+}
+
+// condNode evaluates either ifTrue or ifFalse depending on cond.
+type condNode struct {
+	cond    node
+	ifTrue  node
+	ifFalse node
+}
+
+func (n condNode) evalFloat(x float64) float64 {
+	if n.cond.evalFloat(x) != 0 {
+		return n.ifTrue.evalFloat(x)
+	}
+	return n.ifFalse.evalFloat(x)
+}
+
+func (n condNode) evalInt(x int64) int64 {
+	if n.cond.evalInt(x) != 0 {
+		return n.ifTrue.evalInt(x)
+	}
+	return n.ifFalse.evalInt(x)
+}
+
+func (n condNode) evalBig(x *big.Int) *big.Int {
+	if n.cond.evalBig(x).Sign() != 0 {
+		return n.ifTrue.evalBig(x)
+	}
+	return n.ifFalse.evalBig(x)
+}
+
+type callNode struct {
+	fn   *funcType
+	args []node
+}
+
+func (n callNode) evalFloat(x float64) float64 {
+	args := make([]float64, len(n.args))
+	for i := range args {
+		args[i] = n.args[i].evalFloat(x)
+	}
+	return n.fn.float(args)
+}
+
+func (n callNode) evalInt(x int64) int64 {
+	args := make([]int64, len(n.args))
+	for i := range args {
+		args[i] = n.args[i].evalInt(x)
+	}
+	if n.fn.int == nil {
+		fargs := make([]float64, len(args))
+		for i := range args {
+			fargs[i] = float64(args[i])
+		}
+		return int64(n.fn.float(fargs))
+	}
+	return n.fn.int(args)
+}
+
+func (n callNode) evalBig(x *big.Int) *big.Int {
+	args := make([]*big.Int, len(n.args))
+	for i := range args {
+		args[i] = n.args[i].evalBig(x)
+	}
+	if n.fn.big == nil {
+		fargs := make([]float64, len(args))
+		for i := range args {
+			fargs[i], _ = new(big.Float).SetInt(args[i]).Float64()
+		}
+		return floatToBig(n.fn.float(fargs))
+	}
+	return n.fn.big(args)
+}
+
+// compare returns whether a comparison whose operands compare as cmp
+// holds. cmp is -1, 0, or 1 as in big.Int.Cmp.
+func compare(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		panic("expr: unknown operator " + op)
+	}
+}
+
+// compareFloat compares a and b like big.Int.Cmp. NaN compares unequal
+// to everything.
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	case a == b:
+		return 0
+	default:
+		return 2
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func boolBig(b bool) *big.Int {
+	return big.NewInt(boolInt(b))
+}
+
+// floatToBig returns f truncated toward zero. floatToBig returns 0 for
+// NaN and infinities.
+func floatToBig(f float64) *big.Int {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return new(big.Int)
+	}
+	result, _ := big.NewFloat(f).Int(nil)
+	return result
+}
+
+// powInt returns x^n. Negative n gives 0 unless x is 1 or -1.
+func powInt(x, n int64) int64 {
+	if n < 0 {
+		return negativePower(x, n%2 != 0)
+	}
+	result := int64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result *= x
+		}
+		x *= x
+	}
+	return result
+}
+
+func powBig(x, n *big.Int) *big.Int {
+	if n.Sign() < 0 {
+		if !x.IsInt64() {
+			return new(big.Int)
+		}
+		return big.NewInt(negativePower(x.Int64(), n.Bit(0) == 1))
+	}
+	if n.Sign() == 0 {
+		return big.NewInt(1)
+	}
+	if x.CmpAbs(one) <= 0 {
+		if x.Sign() < 0 && n.Bit(0) == 0 {
+			return new(big.Int).Neg(x)
+		}
+		return new(big.Int).Set(x)
+	}
+
+	// x^n has at least n bits and at most n times as many bits as x.
+	if !n.IsInt64() || n.Int64() > kMaxBits/int64(x.BitLen()) {
+		evalPanic("%v to the power %v too large", x, n)
+	}
+	return new(big.Int).Exp(x, n, nil)
+}
+
+// negativePower returns x^n truncated toward zero for a negative n which
+// is odd or even according to odd.
+func negativePower(x int64, odd bool) int64 {
+	switch x {
+	case 0:
+		evalPanic("0 to a negative power")
+	case 1:
+		return 1
+	case -1:
+		if odd {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+// Package expr compiles expressions in the variable x into functions that
+// work with the Apply methods of package gochart.
+//
+// Expressions may contain x, numbers, the constants pi and e, parentheses,
+// and these operators from lowest to highest precedence:
+//
+//	c ? a : b       conditional, a if c is nonzero or else b
+//	||              logical or, 1 or 0
+//	&&              logical and, 1 or 0
+//	== != < <= > >= comparison, 1 or 0
+//	+ -             addition and subtraction
+//	* / %           multiplication, division, and remainder
+//	- +             unary minus and plus
+//	^               exponent, grouping right to left
+//	!               factorial, as in x!
+//
+// so -x^2 means -(x^2). Expressions may also call these functions:
+//
+//	abs(x) sqrt(x) cbrt(x) exp(x) ln(x) log(x) log2(x) log10(x)
+//	sin(x) cos(x) tan(x) asin(x) acos(x) atan(x) sinh(x) cosh(x) tanh(x)
+//	floor(x) ceil(x) round(x) trunc(x) fact(n) binomial(n, k) choose(n, k)
+//	gcd(a, b) min(a, b, ...) max(a, b, ...) if(c, a, b)
+//
+// log is the natural logarithm like ln.
+package expr
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Expr is a compiled expression in the variable x.
+type Expr struct {
+	source string
+	root   node
+}
+
+// Parse compiles s into an Expr.
+func Parse(s string) (*Expr, error) {
+	p := &parser{lexer: newLexer(s)}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{source: s, root: root}, nil
+}
+
+// MustParse is like Parse but panics if s does not parse.
+func MustParse(s string) *Expr {
+	result, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// String returns the source of this expression.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Float returns this expression as a function suitable for
+// gochart.Floats.Apply. The factorial of a non integer is computed with
+// the gamma function.
+func (e *Expr) Float() func(float64) float64 {
+	return e.root.evalFloat
+}
+
+// Int returns this expression as a function suitable for
+// gochart.Ints.Apply. The function uses integer arithmetic which wraps on
+// overflow: / truncates toward zero; sqrt rounds down; and like Go, the
+// function panics on division by zero. Functions with no integer
+// counterpart such as sin and constants such as pi are computed in
+// floating point and truncated toward zero. binomial wraps too, but since
+// it computes its exact result first, it panics under the same size
+// limits that BigInt has.
+func (e *Expr) Int() func(int64) int64 {
+	return e.root.evalInt
+}
+
+// BigInt returns this expression as a function suitable for
+// gochart.Ints.ApplyBigInt. The function works like the one Int returns
+// except that it never overflows. Functions with no integer counterpart
+// are computed in float64 precision. So that a single expression cannot
+// exhaust memory, the function panics when ^, fact, or binomial would
+// produce a result of more than about a million bits or when fact gets an
+// argument over 50000.
+func (e *Expr) BigInt() func(x int64, result *big.Int) *big.Int {
+	return func(x int64, result *big.Int) *big.Int {
+		return result.Set(e.root.evalBig(big.NewInt(x)))
+	}
+}
+
+// evalError is what compiled functions panic with when an expression
+// cannot be evaluated, such as the factorial of a negative number.
+type evalError string
+
+func (e evalError) Error() string {
+	return string(e)
+}
+
+func evalPanic(format string, args ...interface{}) {
+	panic(evalError(fmt.Sprintf("expr: "+format, args...)))
+}
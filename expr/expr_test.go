@@ -0,0 +1,166 @@
+package expr_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/keep94/gochart/expr"
+)
+
+func TestInt(t *testing.T) {
+	assertInt(t, "x^2 - 2", 3, 7)
+	assertInt(t, "2 + 3 * x", 4, 14)
+	assertInt(t, "(2 + 3) * x", 4, 20)
+	assertInt(t, "-x^2", 3, -9)
+	assertInt(t, "2^3^2", 0, 512)
+	assertInt(t, "2^-x", 1, 0)
+	assertInt(t, "x / 3", -7, -2)
+	assertInt(t, "x % 3", -7, -1)
+	assertInt(t, "10 - 4 - 3", 0, 3)
+}
+
+func TestFloat(t *testing.T) {
+	assertFloat(t, "x / 2", 3, 1.5)
+	assertFloat(t, "x^0.5", 2, math.Sqrt2)
+	assertFloat(t, "x % 2", 5.5, 1.5)
+	assertFloat(t, "1e-3 * x", 2, 0.002)
+	assertFloat(t, "2.5E2 + +x", 1, 251)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{"", "x +", "(x", "x)", "y", "1.2.3", "x $ 2"} {
+		if _, err := expr.Parse(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if s := expr.MustParse("x + 1").String(); s != "x + 1" {
+		t.Errorf("Expected x + 1, got %s", s)
+	}
+}
+
+func assertInt(t *testing.T, s string, x, expected int64) {
+	t.Helper()
+	if actual := expr.MustParse(s).Int()(x); actual != expected {
+		t.Errorf("%s at %d: expected %d, got %d", s, x, expected, actual)
+	}
+}
+
+func assertFloat(t *testing.T, s string, x, expected float64) {
+	t.Helper()
+	if actual := expr.MustParse(s).Float()(x); actual != expected {
+		t.Errorf("%s at %v: expected %v, got %v", s, x, expected, actual)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	assertInt(t, "abs(x)", -4, 4)
+	assertInt(t, "sqrt(x)", 99, 9)
+	assertInt(t, "x!", 5, 120)
+	assertInt(t, "fact(x) / 2", 4, 12)
+	assertInt(t, "binomial(x, 2)", 6, 15)
+	assertInt(t, "choose(x, 3)", -2, -4)
+	assertInt(t, "binomial(x, 7)", 6, 0)
+	assertInt(t, "gcd(x, 18)", -12, 6)
+	assertInt(t, "min(x, 3, 5)", 4, 3)
+	assertInt(t, "max(x, 3, 5)", 4, 5)
+	assertInt(t, "floor(x) + round(x)", 4, 8)
+	assertInt(t, "exp(x) + log2(x)", 3, 21)
+	assertInt(t, "pi * x", 2, 6)
+	assertFloat(t, "sqrt(x)", 2, math.Sqrt2)
+	assertFloat(t, "x!", 0.5, math.Gamma(1.5))
+	assertFloat(t, "x!", 5, 120)
+	assertFloat(t, "binomial(x, 3)", 10, 120)
+	assertFloat(t, "ln(e^x)", 2, 2)
+	assertFloat(t, "log10(x)", 1000, 3)
+	assertFloat(t, "round(x) + floor(x) + ceil(x) + trunc(x)", -1.5, -6)
+	assertFloat(t, "min(x, 1)", 0.5, 0.5)
+	assertFloat(t, "gcd(x, 12)", 18, 6)
+}
+
+func TestConditionals(t *testing.T) {
+	collatz := "x % 2 == 0 ? x / 2 : 3 * x + 1"
+	assertInt(t, collatz, 6, 3)
+	assertInt(t, collatz, 7, 22)
+	assertInt(t, "if(x < 0, -x, x)", -5, 5)
+	assertInt(t, "x > 2 && x <= 4", 3, 1)
+	assertInt(t, "x > 2 && x <= 4", 5, 0)
+	assertInt(t, "x < 2 || x >= 4", 3, 0)
+	assertInt(t, "x != 3", 3, 0)
+	assertInt(t, "x < 0 ? -1 : x == 0 ? 0 : 1", 0, 0)
+	assertFloat(t, "x >= 0.5 ? 1 : 0", 0.5, 1)
+
+	// Only the chosen branch is evaluated.
+	assertInt(t, "x == 0 ? 0 : 1 / x", 0, 0)
+	assertInt(t, "x != 0 && 1 / x == 1", 0, 0)
+}
+
+func TestBigInt(t *testing.T) {
+	assertBigInt(t, "x!", 25, "15511210043330985984000000")
+	assertBigInt(t, "2^x - 1", 89, "618970019642690137449562111")
+	assertBigInt(t, "binomial(2 * x, x)", 40, "107507208733336176461620")
+	assertBigInt(t, "x % 2 == 0 ? x / 2 : 3 * x + 1", 7, "22")
+	assertBigInt(t, "gcd(x!, 2^100)", 10, "256")
+	assertBigInt(t, "sqrt(10^40 + x)", 1, "100000000000000000000")
+	assertBigInt(t, "max(x, 100000000000000000000)", 3, "100000000000000000000")
+	assertBigInt(t, "(-1)^-x + 2^-x", 3, "-1")
+	assertBigInt(t, "exp(x) + log2(x)", 3, "21")
+}
+
+func TestHugeArguments(t *testing.T) {
+	assertInt(t, "fact(x)", 65, -9223372036854775808)
+	assertInt(t, "fact(x)", 66, 0)
+	assertInt(t, "fact(x)", 1000000000000, 0)
+	assertInt(t, "binomial(x, 35)", 70, 1505813374405535736)
+	assertInt(t, "binomial(x, x - 1)", 1000000000000, 1000000000000)
+	assertInt(t, "binomial(x, 0)", 0, 1)
+	assertInt(t, "binomial(x, 0)", -1, 1)
+	assertBigInt(t, "binomial(x, 0)", 0, "1")
+	assertBigInt(t, "binomial(x, 0)", -1, "1")
+	assertFloat(t, "binomial(x, 10^11)", 1e12, math.Inf(1))
+	assertFloat(t, "binomial(x, 10^12)", -1, 1)
+	assertBigInt(t, "x^0", 0, "1")
+	assertBigInt(t, "(-1)^x", 1000000000000000, "1")
+	assertBigInt(t, "x^(10^15)", 1, "1")
+}
+
+func TestEvalPanics(t *testing.T) {
+	assertPanics(t, func() { expr.MustParse("x!").Int()(-1) })
+	assertPanics(t, func() { expr.MustParse("sqrt(x)").Int()(-1) })
+	assertPanics(t, func() { expr.MustParse("x^-1").Int()(0) })
+	assertPanics(t, func() { expr.MustParse("x!").BigInt()(-1, new(big.Int)) })
+	assertPanics(t, func() { expr.MustParse("binomial(x, 10^11)").Int()(1000000000000) })
+	assertPanics(t, func() { expr.MustParse("2^(10^x)").BigInt()(15, new(big.Int)) })
+	assertPanics(t, func() { expr.MustParse("fact(10^x)").BigInt()(12, new(big.Int)) })
+	assertPanics(t, func() { expr.MustParse("binomial(10^x, 10^11)").BigInt()(12, new(big.Int)) })
+}
+
+func TestFunctionErrors(t *testing.T) {
+	for _, s := range []string{
+		"foo(x)", "sqrt(x, 2)", "binomial(x)", "max()", "if(x, 1)",
+		"sqrt x", "x ? 1", "1 < x < 2", "x = 1", "x & 1"} {
+		if _, err := expr.Parse(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
+
+func assertBigInt(t *testing.T, s string, x int64, expected string) {
+	t.Helper()
+	if actual := expr.MustParse(s).BigInt()(x, new(big.Int)); actual.String() != expected {
+		t.Errorf("%s at %d: expected %s, got %v", s, x, expected, actual)
+	}
+}
+
+func assertPanics(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic")
+		}
+	}()
+	f()
+}
@@ -0,0 +1,300 @@
+package expr
+
+import (
+	"math"
+	"math/big"
+)
+
+// funcType is a function that expressions may call.
+type funcType struct {
+
+	// The number of arguments. If variadic is true, numArgs is the minimum
+	// number of arguments.
+	numArgs  int
+	variadic bool
+
+	float func(args []float64) float64
+
+	// nil means compute with float and truncate toward zero.
+	int func(args []int64) int64
+
+	// nil means compute with float and truncate toward zero.
+	big func(args []*big.Int) *big.Int
+}
+
+// kMaxBits bounds the size in bits of the *big.Int results of ^, fact,
+// and binomial so that runaway expressions such as 2^(10^15) fail rather
+// than exhaust memory.
+const kMaxBits = 1 << 20
+
+// kMaxFactorial is the largest n for which fact(n) computes n! as a
+// *big.Int.
+const kMaxFactorial = 50000
+
+var (
+	one       = big.NewInt(1)
+	maxUint64 = new(big.Int).SetUint64(math.MaxUint64)
+)
+
+var kConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+var kFuncs = map[string]*funcType{
+	"abs": {
+		numArgs: 1,
+		float:   func(a []float64) float64 { return math.Abs(a[0]) },
+		int: func(a []int64) int64 {
+			if a[0] < 0 {
+				return -a[0]
+			}
+			return a[0]
+		},
+		big: func(a []*big.Int) *big.Int { return new(big.Int).Abs(a[0]) },
+	},
+	"sqrt": {
+		numArgs: 1,
+		float:   func(a []float64) float64 { return math.Sqrt(a[0]) },
+		int: func(a []int64) int64 {
+			return sqrtBig(big.NewInt(a[0])).Int64()
+		},
+		big: func(a []*big.Int) *big.Int { return sqrtBig(a[0]) },
+	},
+	"cbrt":  floatFunc(math.Cbrt),
+	"exp":   floatFunc(math.Exp),
+	"ln":    floatFunc(math.Log),
+	"log":   floatFunc(math.Log),
+	"log2":  floatFunc(math.Log2),
+	"log10": floatFunc(math.Log10),
+	"sin":   floatFunc(math.Sin),
+	"cos":   floatFunc(math.Cos),
+	"tan":   floatFunc(math.Tan),
+	"asin":  floatFunc(math.Asin),
+	"acos":  floatFunc(math.Acos),
+	"atan":  floatFunc(math.Atan),
+	"sinh":  floatFunc(math.Sinh),
+	"cosh":  floatFunc(math.Cosh),
+	"tanh":  floatFunc(math.Tanh),
+	"floor": roundFunc(math.Floor),
+	"ceil":  roundFunc(math.Ceil),
+	"round": roundFunc(math.Round),
+	"trunc": roundFunc(math.Trunc),
+	"fact": {
+		numArgs: 1,
+		float:   func(a []float64) float64 { return factFloat(a[0]) },
+		int:     func(a []int64) int64 { return factInt(a[0]) },
+		big:     func(a []*big.Int) *big.Int { return factBig(a[0]) },
+	},
+	"binomial": binomialFunc,
+	"choose":   binomialFunc,
+	"gcd": {
+		numArgs: 2,
+		float:   func(a []float64) float64 { return gcdFloat(a[0], a[1]) },
+		int:     func(a []int64) int64 { return gcdInt(a[0], a[1]) },
+		big: func(a []*big.Int) *big.Int {
+			return new(big.Int).GCD(nil, nil, a[0], a[1])
+		},
+	},
+	"min": extremeFunc(-1),
+	"max": extremeFunc(1),
+}
+
+var binomialFunc = &funcType{
+	numArgs: 2,
+	float:   func(a []float64) float64 { return binomialFloat(a[0], a[1]) },
+	int:     func(a []int64) int64 { return binomialInt(a[0], a[1]) },
+	big:     func(a []*big.Int) *big.Int { return binomialBig(a[0], a[1]) },
+}
+
+// floatFunc returns a function of one argument that has no integer
+// counterpart.
+func floatFunc(f func(float64) float64) *funcType {
+	return &funcType{
+		numArgs: 1,
+		float:   func(a []float64) float64 { return f(a[0]) },
+	}
+}
+
+// roundFunc returns a function of one argument that leaves integers
+// unchanged.
+func roundFunc(f func(float64) float64) *funcType {
+	return &funcType{
+		numArgs: 1,
+		float:   func(a []float64) float64 { return f(a[0]) },
+		int:     func(a []int64) int64 { return a[0] },
+		big:     func(a []*big.Int) *big.Int { return a[0] },
+	}
+}
+
+// extremeFunc returns min if sign is -1 or max if sign is 1.
+func extremeFunc(sign int) *funcType {
+	return &funcType{
+		numArgs:  1,
+		variadic: true,
+		float: func(a []float64) float64 {
+			result := a[0]
+			for _, v := range a[1:] {
+				if sign < 0 {
+					result = math.Min(result, v)
+				} else {
+					result = math.Max(result, v)
+				}
+			}
+			return result
+		},
+		int: func(a []int64) int64 {
+			result := a[0]
+			for _, v := range a[1:] {
+				if compareInt(v, result) == sign {
+					result = v
+				}
+			}
+			return result
+		},
+		big: func(a []*big.Int) *big.Int {
+			result := a[0]
+			for _, v := range a[1:] {
+				if v.Cmp(result) == sign {
+					result = v
+				}
+			}
+			return result
+		},
+	}
+}
+
+// sqrtBig returns the square root of x rounded down.
+func sqrtBig(x *big.Int) *big.Int {
+	if x.Sign() < 0 {
+		evalPanic("square root of negative number %v", x)
+	}
+	return new(big.Int).Sqrt(x)
+}
+
+// factFloat returns n!. If n is not an integer, factFloat uses the gamma
+// function.
+func factFloat(n float64) float64 {
+	if n != math.Trunc(n) {
+		return math.Gamma(n + 1)
+	}
+	if n < 0 {
+		return math.NaN()
+	}
+	result := 1.0
+	for i := 2.0; i <= n && !math.IsInf(result, 0); i++ {
+		result *= i
+	}
+	return result
+}
+
+func factInt(n int64) int64 {
+	if n < 0 {
+		evalPanic("factorial of negative number %d", n)
+	}
+	// 66! and beyond are multiples of 2^64 so they wrap to 0.
+	if n >= 66 {
+		return 0
+	}
+	result := int64(1)
+	for i := int64(2); i <= n; i++ {
+		result *= i
+	}
+	return result
+}
+
+func factBig(n *big.Int) *big.Int {
+	if n.Sign() < 0 {
+		evalPanic("factorial of negative number %v", n)
+	}
+	if n.Cmp(big.NewInt(kMaxFactorial)) > 0 {
+		evalPanic("factorial of %v too large", n)
+	}
+	return new(big.Int).MulRange(1, n.Int64())
+}
+
+// binomialFloat returns n choose k. If n or k is not an integer,
+// binomialFloat uses the gamma function.
+func binomialFloat(n, k float64) float64 {
+	if n != math.Trunc(n) || k != math.Trunc(k) {
+		return math.Gamma(n+1) / (math.Gamma(k+1) * math.Gamma(n-k+1))
+	}
+	if k < 0 || (n >= 0 && k > n) {
+		return 0
+	}
+	if n < 0 {
+		result := binomialFloat(k-n-1, k)
+		if math.Mod(k, 2) != 0 {
+			return -result
+		}
+		return result
+	}
+	if k > n-k {
+		k = n - k
+	}
+
+	// The partial products are n choose i+1 which only grow, so stop at
+	// infinity.
+	result := 1.0
+	for i := 0.0; i < k && !math.IsInf(result, 0); i++ {
+		result = result * (n - i) / (i + 1)
+	}
+	return math.Round(result)
+}
+
+// binomialInt returns n choose k wrapped to 64 bits. For negative n,
+// binomialInt returns n(n-1)...(n-k+1)/k!.
+func binomialInt(n, k int64) int64 {
+	return wrapInt64(binomialBig(big.NewInt(n), big.NewInt(k)))
+}
+
+func binomialBig(n, k *big.Int) *big.Int {
+	if k.Sign() < 0 || (n.Sign() >= 0 && k.Cmp(n) > 0) {
+		return new(big.Int)
+	}
+	if n.Sign() < 0 {
+		result := binomialBig(new(big.Int).Sub(new(big.Int).Sub(k, n), one), k)
+		if k.Bit(0) == 1 {
+			result.Neg(result)
+		}
+		return result
+	}
+	smallK := k
+	if nMinusK := new(big.Int).Sub(n, k); nMinusK.Cmp(k) < 0 {
+		smallK = nMinusK
+	}
+
+	if smallK.Sign() == 0 {
+		return big.NewInt(1)
+	}
+
+	// n choose k has at most k times as many bits as n.
+	if !smallK.IsInt64() || !n.IsInt64() ||
+		smallK.Int64() > kMaxBits/int64(n.BitLen()) {
+		evalPanic("binomial of %v and %v too large", n, k)
+	}
+	return new(big.Int).Binomial(n.Int64(), smallK.Int64())
+}
+
+// wrapInt64 returns the low 64 bits of x in two's complement.
+func wrapInt64(x *big.Int) int64 {
+	return int64(new(big.Int).And(x, maxUint64).Uint64())
+}
+
+func gcdInt(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func gcdFloat(a, b float64) float64 {
+	a, b = math.Abs(a), math.Abs(b)
+	for b != 0 {
+		a, b = b, math.Mod(a, b)
+	}
+	return a
+}
@@ -0,0 +1,372 @@
+package expr
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	kEOF tokenKind = iota
+	kNumber
+	kIdent
+	kOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == kEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos)
+}
+
+type lexer struct {
+	runes []rune
+	pos   int
+}
+
+func newLexer(s string) *lexer {
+	return &lexer{runes: []rune(s)}
+}
+
+func (l *lexer) next() token {
+	for l.pos < len(l.runes) && unicode.IsSpace(l.runes[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos == len(l.runes) {
+		return token{kind: kEOF, pos: start}
+	}
+	r := l.runes[l.pos]
+	switch {
+	case unicode.IsDigit(r) || r == '.':
+		for l.pos < len(l.runes) && isNumberRune(l.runes, l.pos) {
+			l.pos++
+		}
+		return token{kind: kNumber, text: string(l.runes[start:l.pos]), pos: start}
+	case unicode.IsLetter(r) || r == '_':
+		for l.pos < len(l.runes) && (unicode.IsLetter(l.runes[l.pos]) ||
+			unicode.IsDigit(l.runes[l.pos]) || l.runes[l.pos] == '_') {
+			l.pos++
+		}
+		return token{kind: kIdent, text: string(l.runes[start:l.pos]), pos: start}
+	default:
+		if l.pos+1 < len(l.runes) {
+			if op := string(l.runes[l.pos : l.pos+2]); kTwoCharOperators[op] {
+				l.pos += 2
+				return token{kind: kOperator, text: op, pos: start}
+			}
+		}
+		l.pos++
+		return token{kind: kOperator, text: string(r), pos: start}
+	}
+}
+
+var kTwoCharOperators = map[string]bool{
+	"==": true,
+	"!=": true,
+	"<=": true,
+	">=": true,
+	"&&": true,
+	"||": true,
+}
+
+// isNumberRune returns true if runes[pos] continues a number. It accepts
+// digits, decimal points, and exponents such as 1e-6.
+func isNumberRune(runes []rune, pos int) bool {
+	r := runes[pos]
+	if unicode.IsDigit(r) || r == '.' || r == 'e' || r == 'E' {
+		return true
+	}
+	if (r == '+' || r == '-') && pos > 0 {
+		prev := runes[pos-1]
+		return prev == 'e' || prev == 'E'
+	}
+	return false
+}
+
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+func (p *parser) parse() (node, error) {
+	p.advance()
+	result, err := p.parseCond()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != kEOF {
+		return nil, p.unexpected()
+	}
+	return result, nil
+}
+
+func (p *parser) advance() {
+	p.tok = p.lexer.next()
+}
+
+// isOperator returns true if the current token is one of ops.
+func (p *parser) isOperator(ops ...string) bool {
+	if p.tok.kind != kOperator {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// expect consumes the operator op or returns an error.
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return p.unexpected()
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	return fmt.Errorf("expr: unexpected %v", p.tok)
+}
+
+// parseCond parses an optional conditional: c ? a : b.
+func (p *parser) parseCond() (node, error) {
+	cond, err := p.parseLogical("||")
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("?") {
+		return cond, nil
+	}
+	p.advance()
+	ifTrue, err := p.parseCond()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	ifFalse, err := p.parseCond()
+	if err != nil {
+		return nil, err
+	}
+	return condNode{cond: cond, ifTrue: ifTrue, ifFalse: ifFalse}, nil
+}
+
+// parseLogical parses operands separated by op which is || or &&. The
+// operands of || are separated by &&.
+func (p *parser) parseLogical(op string) (node, error) {
+	parseOperand := p.parseComparison
+	if op == "||" {
+		parseOperand = func() (node, error) { return p.parseLogical("&&") }
+	}
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(op) {
+		p.advance()
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: op == "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseComparison parses a sum optionally compared to another sum.
+// Comparisons do not chain.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("==", "!=", "<", "<=", ">", ">=") {
+		return left, nil
+	}
+	op := p.tok.text
+	p.advance()
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, left: left, right: right}, nil
+}
+
+// parseSum parses terms separated by + or -.
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.tok.text
+		p.advance()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseProduct parses factors separated by *, /, or %.
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.tok.text
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses an optional unary minus or plus followed by a power.
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("-") {
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{operand: operand}, nil
+	}
+	if p.isOperator("+") {
+		p.advance()
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+// parsePower parses a factorial optionally raised to a power.
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parseFactorial()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("^") {
+		return base, nil
+	}
+	p.advance()
+
+	// Exponents group right to left and may be negative as in 2^-x.
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: "^", left: base, right: exponent}, nil
+}
+
+// parseFactorial parses a primary followed by any number of !.
+func (p *parser) parseFactorial() (node, error) {
+	result, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("!") {
+		p.advance()
+		result = factNode{operand: result}
+	}
+	return result, nil
+}
+
+// parsePrimary parses a number, x, a constant, a function call, or a
+// parenthesized expression.
+func (p *parser) parsePrimary() (node, error) {
+	switch {
+	case p.tok.kind == kNumber:
+		result, err := newNumNode(p.tok.text)
+		if err != nil {
+			return nil, err
+		}
+		p.advance()
+		return result, nil
+	case p.tok.kind == kIdent:
+		return p.parseIdent()
+	case p.isOperator("("):
+		p.advance()
+		result, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return result, nil
+	default:
+		return nil, p.unexpected()
+	}
+}
+
+// parseIdent parses x, a constant, or a function call.
+func (p *parser) parseIdent() (node, error) {
+	name := p.tok
+	p.advance()
+	if name.text == "x" {
+		return varNode{}, nil
+	}
+	if value, ok := kConstants[name.text]; ok {
+		return newConstNode(value), nil
+	}
+	fn, ok := kFuncs[name.text]
+	if !ok && name.text != "if" {
+		return nil, fmt.Errorf("expr: unknown name %v", name)
+	}
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	if name.text == "if" {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("expr: if needs 3 arguments at position %d", name.pos)
+		}
+		return condNode{cond: args[0], ifTrue: args[1], ifFalse: args[2]}, nil
+	}
+	if len(args) < fn.numArgs || (!fn.variadic && len(args) > fn.numArgs) {
+		return nil, fmt.Errorf(
+			"expr: wrong number of arguments to %v", name)
+	}
+	return callNode{fn: fn, args: args}, nil
+}
+
+// parseArgs parses a parenthesized, comma separated list of arguments.
+func (p *parser) parseArgs() ([]node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var result []node
+	for {
+		arg, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, arg)
+		if !p.isOperator(",") {
+			break
+		}
+		p.advance()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
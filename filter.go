@@ -0,0 +1,52 @@
+package gochart
+
+// Filter returns the X and Y values from xs and ys for which pred returns
+// true. The returned X values are the original X values, not re-indexed
+// ones, so the returned values are ready for NewChart. xs and ys must be
+// the same length or else Filter panics.
+func Filter(
+	xs, ys Values, pred func(x, y interface{}) bool) (Values, Values) {
+	if xs.Len() != ys.Len() {
+		panic("xs and ys must have same length")
+	}
+	var resultXs, resultYs valueSlice
+	for i := 0; i < xs.Len(); i++ {
+		x, y := xs.Value(i), ys.Value(i)
+		if pred(x, y) {
+			resultXs = append(resultXs, x)
+			resultYs = append(resultYs, y)
+		}
+	}
+	return resultXs, resultYs
+}
+
+// Sample returns every nth X and Y value from xs and ys starting with the
+// first. n must be positive. xs and ys must be the same length or else
+// Sample panics.
+func Sample(xs, ys Values, n int) (Values, Values) {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	i := 0
+	return Filter(xs, ys, func(x, y interface{}) bool {
+		keep := i%n == 0
+		i++
+		return keep
+	})
+}
+
+// Changes returns the X and Y values from xs and ys where the Y value
+// differs from the Y value just before it. The first X and Y value are
+// always included. Numbers of different types are equal if they have the
+// same value, so int64(2) equals 2.0. xs and ys must be the same length or
+// else Changes panics.
+func Changes(xs, ys Values) (Values, Values) {
+	var prev interface{}
+	first := true
+	return Filter(xs, ys, func(x, y interface{}) bool {
+		keep := first || !valuesEqual(prev, y)
+		prev = y
+		first = false
+		return keep
+	})
+}
@@ -0,0 +1,62 @@
+package gochart_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestFilter(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 10)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	fxs, fys := gochart.Filter(xs, ys, func(x, y interface{}) bool {
+		return y.(int64)%3 == 1
+	})
+	assertValuesEqual(
+		t, fxs,
+		int64(1), int64(2), int64(4), int64(5), int64(7), int64(8), int64(10))
+	assertValuesEqual(
+		t, fys,
+		int64(1), int64(4), int64(16), int64(25), int64(49), int64(64),
+		int64(100))
+	assertPanic(t, func() {
+		gochart.Filter(xs, gochart.NewInts(1, 1, 9), nil)
+	})
+}
+
+func TestSample(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 10)
+	ys := xs.Apply(func(x int64) int64 { return -x })
+	sxs, sys := gochart.Sample(xs, ys, 4)
+	assertValuesEqual(t, sxs, int64(1), int64(5), int64(9))
+	assertValuesEqual(t, sys, int64(-1), int64(-5), int64(-9))
+	assertPanic(t, func() { gochart.Sample(xs, ys, 0) })
+}
+
+func TestChanges(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 7)
+	ys := xs.ApplyBigInt(func(x int64, result *big.Int) *big.Int {
+		return result.SetInt64(x / 3)
+	})
+	cxs, cys := gochart.Changes(xs, ys)
+	assertValuesEqual(t, cxs, int64(1), int64(3), int64(6))
+	assertBigValuesEqual(t, cys, 0, 1, 2)
+	chart := gochart.NewChart(cxs, cys)
+	assertChart(
+		t,
+		chart,
+		"+-+-+",
+		"|1|0|",
+		"|3|1|",
+		"|6|2|",
+		"+-+-+",
+	)
+}
+
+func TestChangesEmpty(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 0)
+	cxs, cys := gochart.Changes(xs, xs)
+	assertEqual(t, 0, cxs.Len())
+	assertEqual(t, 0, cys.Len())
+}
@@ -0,0 +1,173 @@
+package gochart
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"html"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	kSVGFontSize   = 15
+	kSVGCharWidth  = 9
+	kSVGLineHeight = 18
+)
+
+// WriteCSV writes the values of this chart to w as CSV, one record per X
+// value in order. Each record holds the formatted X value followed by the
+// formatted Y values. WriteCSV ignores layout options such as NumRows
+// and omits any footer. If w is nil, WriteCSV writes to stdout.
+func (c *Chart) WriteCSV(w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+	cw := csv.NewWriter(w)
+	for i := range c.xyValues {
+		record := make([]string, len(c.widths))
+		for k := range record {
+			record[k] = c.xyValues[i].cell(k)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the values of this chart to w as a JSON array with one
+// element for each X value in order. Each element is an array of strings
+// holding the formatted X value followed by the formatted Y values.
+// Like WriteCSV, WriteJSON ignores layout options and omits any footer.
+// If w is nil, WriteJSON writes to stdout.
+func (c *Chart) WriteJSON(w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+	records := make([][]string, len(c.xyValues))
+	for i := range c.xyValues {
+		records[i] = make([]string, len(c.widths))
+		for k := range records[i] {
+			records[i][k] = c.xyValues[i].cell(k)
+		}
+	}
+	return json.NewEncoder(w).Encode(records)
+}
+
+// WriteSVG writes this chart to w as an SVG image of the text that
+// WriteTo writes in a monospace font. WriteSVG never styles the text.
+// If w is nil, WriteSVG writes to stdout.
+func (c *Chart) WriteSVG(w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+	var text bytes.Buffer
+	c.write(&chartWriter{w: &text})
+	var lines []string
+	width := 0
+	scanner := bufio.NewScanner(&text)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		width = maxInt(width, cellWidth(scanner.Text()))
+	}
+	cw := &chartWriter{w: w}
+	cw.printf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" "+
+			"font-family=\"monospace\" font-size=\"%d\">\n",
+		width*kSVGCharWidth,
+		len(lines)*kSVGLineHeight,
+		kSVGFontSize)
+	for i, line := range lines {
+		cw.printf(
+			"<text x=\"0\" y=\"%d\" xml:space=\"preserve\">%s</text>\n",
+			(i+1)*kSVGLineHeight-4,
+			html.EscapeString(line))
+	}
+	cw.println("</svg>")
+	return cw.err
+}
+
+// WriteMarkdown writes this chart to w as a markdown table with the same
+// rows and columns as WriteTo. The table has an empty header row since
+// charts have no column titles. If w is nil, WriteMarkdown writes to
+// stdout.
+func (c *Chart) WriteMarkdown(w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+	cw := &chartWriter{w: w}
+	numCells := c.numCols * len(c.widths)
+	cw.printf("|%s\n", strings.Repeat(" |", numCells))
+	cw.printf("|%s\n", strings.Repeat("--:|", numCells))
+	c.writeLayout(func(row xyValuesType, isFooter bool) {
+		for j := range row {
+			for k := range c.widths {
+				cw.printf("|%s", markdownEscaper.Replace(row[j].cell(k)))
+			}
+		}
+		cw.println("|")
+	})
+	return cw.err
+}
+
+// WriteHTML writes this chart to w as an HTML table with the same rows
+// and columns as WriteTo. Any footer goes in a tfoot element. WriteHTML
+// escapes all values. If w is nil, WriteHTML writes to stdout.
+func (c *Chart) WriteHTML(w io.Writer) error {
+	if w == nil {
+		w = os.Stdout
+	}
+	cw := &chartWriter{w: w}
+	cw.println("<table>")
+	cw.println("<tbody>")
+	inFooter := false
+	c.writeLayout(func(row xyValuesType, isFooter bool) {
+		if isFooter && !inFooter {
+			cw.println("</tbody>")
+			cw.println("<tfoot>")
+			inFooter = true
+		}
+		cw.printf("<tr>")
+		for j := range row {
+			for k := range c.widths {
+				cw.printf("<td>%s</td>", html.EscapeString(row[j].cell(k)))
+			}
+		}
+		cw.println("</tr>")
+	})
+	if inFooter {
+		cw.println("</tfoot>")
+	} else {
+		cw.println("</tbody>")
+	}
+	cw.println("</table>")
+	return cw.err
+}
+
+// writeLayout calls writeRow with each row of this chart as WriteTo lays
+// it out, followed by the rows of the footer, if any.
+func (c *Chart) writeLayout(writeRow func(row xyValuesType, isFooter bool)) {
+	for i := 0; i < c.numRows; i++ {
+		row := make(xyValuesType, c.numCols)
+		for j := range row {
+			row[j] = c.xy(i, j)
+		}
+		writeRow(row, false)
+	}
+	footerRows := (len(c.footer) + c.numCols - 1) / c.numCols
+	for i := 0; i < footerRows; i++ {
+		row := make(xyValuesType, c.numCols)
+		for j := range row {
+			if idx := i*c.numCols + j; idx < len(c.footer) {
+				row[j] = c.footer[idx]
+			}
+		}
+		writeRow(row, true)
+	}
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, `\`, `\\`)
@@ -0,0 +1,104 @@
+package gochart_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestWriteCSV(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.NumCols(2),
+		gochart.Column(stringValues(xs, "a,b", "c", "d"), "%s"),
+		gochart.Footer(gochart.StatSum))
+	var builder strings.Builder
+	if err := chart.WriteCSV(&builder); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "1,1,\"a,b\"\n2,4,c\n3,9,d\n", builder.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	ys := stringValues(xs, "a|b", "c", "d")
+	chart := gochart.NewChart(xs, ys, gochart.NumCols(2))
+	var builder strings.Builder
+	if err := chart.WriteMarkdown(&builder); err != nil {
+		t.Fatal(err)
+	}
+	expected := `| | | | |
+|--:|--:|--:|--:|
+|1|a\|b|3|d|
+|2|c|||
+`
+	assertEqual(t, expected, builder.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 2)
+	ys := xs.Apply(func(x int64) int64 { return x * 3 })
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.YFormat("<%d>"),
+		gochart.Footer(gochart.StatSum))
+	var builder strings.Builder
+	if err := chart.WriteHTML(&builder); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<table>
+<tbody>
+<tr><td>1</td><td>&lt;3&gt;</td></tr>
+<tr><td>2</td><td>&lt;6&gt;</td></tr>
+</tbody>
+<tfoot>
+<tr><td>sum</td><td>&lt;9&gt;</td></tr>
+</tfoot>
+</table>
+`
+	assertEqual(t, expected, builder.String())
+}
+
+// stringValues returns strs as Values. xs holds 1, 2, 3, ...
+func stringValues(xs gochart.Values, strs ...string) gochart.Values {
+	return gochart.Map(xs, func(x interface{}) interface{} {
+		return strs[x.(int64)-1]
+	})
+}
+
+func TestWriteJSON(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 2)
+	ys := stringValues(xs, "a\"b", "c")
+	var builder strings.Builder
+	if err := gochart.NewChart(xs, ys).WriteJSON(&builder); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "[[\"1\",\"a\\\"b\"],[\"2\",\"c\"]]\n", builder.String())
+}
+
+func TestWriteSVG(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 1)
+	ys := stringValues(xs, "<")
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.AlwaysStyle(),
+		gochart.Highlight(
+			func(x, y interface{}) bool { return true }, gochart.Bold))
+	var builder strings.Builder
+	if err := chart.WriteSVG(&builder); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="45" height="54" font-family="monospace" font-size="15">
+<text x="0" y="14" xml:space="preserve">+-+-+</text>
+<text x="0" y="32" xml:space="preserve">|1|&lt;|</text>
+<text x="0" y="50" xml:space="preserve">+-+-+</text>
+</svg>
+`
+	assertEqual(t, expected, builder.String())
+}
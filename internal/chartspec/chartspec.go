@@ -0,0 +1,267 @@
+// Package chartspec builds charts from the textual settings that the
+// gochart command and the charthttp package accept.
+package chartspec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gochart/expr"
+)
+
+// Spec describes a chart of an expression in x or of a named sequence.
+type Spec struct {
+
+	// The first X value, the increment between X values, and the number
+	// of X values. Start and Step are strings so that integers and
+	// floating point numbers can be told apart.
+	Start string
+	Step  string
+	Count int
+
+	// Exactly one of Expr and Seq must be set. Expr is an expression in x
+	// as package expr accepts. Seq names a sequence in Sequences.
+	Expr string
+	Seq  string
+
+	// Float uses floating point arithmetic for Expr even if Start and
+	// Step are integers. Big uses integer arithmetic that never overflows.
+	Float bool
+	Big   bool
+
+	// The number of rows and columns. 0 means as needed.
+	Rows int
+	Cols int
+
+	// The number of fraction digits for floating point X and Y values.
+	// -1 means as needed. Integer values always show in full.
+	XDigits int
+	YDigits int
+
+	// The registry for looking up Seq. nil means gochart.Sequences.
+	Sequences *gochart.SequenceRegistry
+
+	// If non nil, Chart gives up with an error once Done is closed. Chart
+	// checks Done before evaluating Expr at each X value. Sequences
+	// cannot be interrupted.
+	Done <-chan struct{}
+}
+
+// Format is an output format for a chart.
+type Format struct {
+
+	// The MIME type of the format
+	ContentType string
+
+	// Write writes a chart to w in this format.
+	Write func(c *gochart.Chart, w io.Writer) error
+}
+
+// Formats maps format names to formats.
+var Formats = map[string]Format{
+	"text": {
+		ContentType: "text/plain; charset=utf-8",
+		Write: func(c *gochart.Chart, w io.Writer) error {
+			_, err := c.WriteTo(w)
+			return err
+		},
+	},
+	"csv": {
+		ContentType: "text/csv; charset=utf-8",
+		Write:       (*gochart.Chart).WriteCSV,
+	},
+	"markdown": {
+		ContentType: "text/markdown; charset=utf-8",
+		Write:       (*gochart.Chart).WriteMarkdown,
+	},
+	"html": {
+		ContentType: "text/html; charset=utf-8",
+		Write:       (*gochart.Chart).WriteHTML,
+	},
+	"json": {
+		ContentType: "application/json",
+		Write:       (*gochart.Chart).WriteJSON,
+	},
+	"svg": {
+		ContentType: "image/svg+xml",
+		Write:       (*gochart.Chart).WriteSVG,
+	},
+}
+
+// Chart builds the chart that s describes. Chart reports panics while
+// computing values, such as integer division by zero, as errors.
+func (s *Spec) Chart() (chart *gochart.Chart, err error) {
+	if (s.Expr == "") == (s.Seq == "") {
+		return nil, errors.New("gochart: need either an expression or a sequence")
+	}
+	if s.Count < 0 {
+		return nil, fmt.Errorf("gochart: invalid count %d", s.Count)
+	}
+	if s.Float && s.Big {
+		return nil, errors.New("gochart: float and big are mutually exclusive")
+	}
+	var xs, ys gochart.Values
+	if s.Seq != "" {
+		xs, ys, err = s.applySequence()
+	} else {
+		xs, ys, err = s.applyExpr()
+	}
+	if err != nil {
+		return nil, err
+	}
+	options := gochart.Options{gochart.NumRows(s.Rows), gochart.NumCols(s.Cols)}
+
+	// Only Expr applied to floating point X values gives floating point
+	// values. %f would garble integers.
+	if _, ok := xs.(*gochart.Floats); ok {
+		if s.XDigits >= 0 {
+			options = append(options, gochart.XFormat(fmt.Sprintf("%%.%df", s.XDigits)))
+		}
+		if s.YDigits >= 0 {
+			options = append(options, gochart.YFormat(fmt.Sprintf("%%.%df", s.YDigits)))
+		}
+	}
+	return gochart.NewChart(xs, ys, options), nil
+}
+
+// applyExpr returns the X values of s along with Expr applied to each
+// one. applyExpr uses integer arithmetic unless Float is true or Start or
+// Step is not an integer.
+func (s *Spec) applyExpr() (xs gochart.Values, ys gochart.Values, err error) {
+	e, err := expr.Parse(s.Expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.Float {
+		ints, intsErr := s.ints()
+		if intsErr == nil {
+			ys, err := ApplyExpr(e, ints, s.Big, s.Done)
+			return ints, ys, err
+		}
+		if s.Big {
+			return nil, nil, errors.New(
+				"gochart: big requires integer start and step")
+		}
+	}
+	start, err := strconv.ParseFloat(s.Start, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gochart: invalid start %q", s.Start)
+	}
+	step, err := strconv.ParseFloat(s.Step, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gochart: invalid step %q", s.Step)
+	}
+	floats := gochart.NewFloats(start, step, s.Count)
+	ys, err = ApplyExpr(e, floats, false, s.Done)
+	return floats, ys, err
+}
+
+// applySequence returns the X values of s along with Seq applied to each
+// one.
+func (s *Spec) applySequence() (
+	xs gochart.Values, ys gochart.Values, err error) {
+	ints, err := s.ints()
+	if err != nil {
+		return nil, nil, err
+	}
+	ys, err = ApplySequence(s.Sequences, s.Seq, ints)
+	return ints, ys, err
+}
+
+// ApplyExpr applies e to xs which must come from gochart.NewInts or
+// gochart.NewFloats. For Ints, ApplyExpr uses integer arithmetic which
+// never overflows if bigInts is true. If done is non nil,
+// ApplyExpr gives up with an error once done is closed, checking before
+// evaluating e at each X value. ApplyExpr reports panics such as integer
+// division by zero as errors.
+func ApplyExpr(
+	e *expr.Expr, xs gochart.Values, bigInts bool, done <-chan struct{}) (
+	ys gochart.Values, err error) {
+	defer RecoverPanic(&err)
+	switch v := xs.(type) {
+	case *gochart.Ints:
+		if bigInts {
+			f := e.BigInt()
+			return v.ApplyBigInt(func(x int64, result *big.Int) *big.Int {
+				checkDone(done)
+				return f(x, result)
+			}), nil
+		}
+		f := e.Int()
+		return v.Apply(func(x int64) int64 {
+			checkDone(done)
+			return f(x)
+		}), nil
+	case *gochart.Floats:
+		if bigInts {
+			return nil, errors.New("gochart: big requires integer X values")
+		}
+		f := e.Float()
+		return v.Apply(func(x float64) float64 {
+			checkDone(done)
+			return f(x)
+		}), nil
+	}
+	return nil, errors.New(
+		"gochart: expression needs X values from NewInts or NewFloats")
+}
+
+// ApplySequence applies the sequence that spec names in registry to xs
+// which must come from gochart.NewInts. nil registry means
+// gochart.Sequences. ApplySequence reports panics such as X values that
+// are not positive as errors.
+func ApplySequence(
+	registry *gochart.SequenceRegistry, spec string, xs gochart.Values) (
+	ys gochart.Values, err error) {
+	if registry == nil {
+		registry = gochart.Sequences
+	}
+	sequence, err := registry.Lookup(spec)
+	if err != nil {
+		return nil, err
+	}
+	ints, ok := xs.(*gochart.Ints)
+	if !ok {
+		return nil, fmt.Errorf(
+			"gochart: sequence %q needs integer X values", spec)
+	}
+	defer RecoverPanic(&err)
+	return sequence.Apply(ints), nil
+}
+
+// checkDone panics with errCanceled if done is closed.
+func checkDone(done <-chan struct{}) {
+	select {
+	case <-done:
+		panic(errCanceled)
+	default:
+	}
+}
+
+// ints returns the X values of s as integers.
+func (s *Spec) ints() (*gochart.Ints, error) {
+	start, err := strconv.ParseInt(s.Start, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("gochart: invalid start %q", s.Start)
+	}
+	step, err := strconv.ParseInt(s.Step, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("gochart: invalid step %q", s.Step)
+	}
+	return gochart.NewInts(start, step, s.Count), nil
+}
+
+var errCanceled = errors.New("computation canceled")
+
+// RecoverPanic turns a panic such as integer division by zero or a
+// sequence given X values that are not positive into an error stored in
+// err. Callers defer RecoverPanic.
+func RecoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("gochart: %v", r)
+	}
+}
@@ -0,0 +1,195 @@
+package gochart
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrOutOfRange means that no X value between the bounds of an Inverse
+// maps to the requested Y value.
+var ErrOutOfRange = errors.New("gochart: value out of range")
+
+// Inverse tells how to invert a function. Unlike Floats.ApplyInv, which
+// silently returns a bound when asked to invert a value out of range,
+// Inverse reports such values as errors or NaN. Inverse also handles
+// piecewise monotone functions.
+type Inverse struct {
+
+	// Lower and Upper bound the X values to search.
+	Lower float64
+	Upper float64
+
+	// The number of equal width brackets into which to divide the range
+	// from Lower to Upper. The function must be monotone within each
+	// bracket. Solutions are sought in each bracket from lowest to highest.
+	// 0 means 1.
+	Brackets int
+
+	// Searching stops once a solution is known within Tolerance. 0 means
+	// search to the full precision of float64.
+	Tolerance float64
+
+	// The maximum number of bisections for each solution. 0 means 200.
+	MaxIterations int
+
+	// If true, ApplyInverse returns NaN for values out of range instead of
+	// reporting ErrOutOfRange.
+	NaN bool
+}
+
+// InverseResult is a solution found by Inverse.
+type InverseResult struct {
+
+	// The solution
+	X float64
+
+	// The number of bisections used to find X.
+	Iterations int
+
+	// X is within Tolerance of the exact solution.
+	Tolerance float64
+}
+
+// Solve returns the smallest x between Lower and Upper such that
+// fn(x) = y. If there is no such x, Solve returns an error wrapping
+// ErrOutOfRange.
+func (inv Inverse) Solve(
+	fn func(float64) float64, y float64) (InverseResult, error) {
+	var result InverseResult
+	found := inv.solve(fn, y, func(r InverseResult) bool {
+		result = r
+		return false
+	})
+	if !found {
+		return InverseResult{}, inv.outOfRange(y)
+	}
+	return result, nil
+}
+
+// SolveAll returns one x for each bracket between Lower and Upper such
+// that fn(x) = y, ordered from lowest to highest. If there is no such x,
+// SolveAll returns an error wrapping ErrOutOfRange.
+func (inv Inverse) SolveAll(
+	fn func(float64) float64, y float64) ([]InverseResult, error) {
+	var result []InverseResult
+	inv.solve(fn, y, func(r InverseResult) bool {
+		result = append(result, r)
+		return true
+	})
+	if len(result) == 0 {
+		return nil, inv.outOfRange(y)
+	}
+	return result, nil
+}
+
+// ApplyInverse applies the inverse of fn to each of these X values using
+// inv and returns the resulting Y values, each the smallest solution that
+// inv.Solve finds. If some X value is out of range, ApplyInverse returns
+// an error wrapping ErrOutOfRange unless inv.NaN is true, in which case
+// the corresponding Y value is NaN.
+func (f *Floats) ApplyInverse(
+	fn func(float64) float64, inv Inverse) (Values, error) {
+	result := make(valueSlice, f.count)
+	for i := 0; i < f.count; i++ {
+		r, err := inv.Solve(fn, f.value(i))
+		if err != nil && !inv.NaN {
+			return nil, err
+		}
+		if err != nil {
+			result[i] = math.NaN()
+		} else {
+			result[i] = r.X
+		}
+	}
+	return result, nil
+}
+
+// solve calls found with each solution of fn(x) = y from lowest to
+// highest until found returns false. solve returns true if it found at
+// least one solution.
+func (inv Inverse) solve(
+	fn func(float64) float64,
+	y float64,
+	found func(r InverseResult) bool) bool {
+	brackets := inv.Brackets
+	if brackets <= 0 {
+		brackets = 1
+	}
+	width := (inv.Upper - inv.Lower) / float64(brackets)
+	result := false
+	lower, flower := inv.Lower, fn(inv.Lower)
+	for i := 1; i <= brackets; i++ {
+		upper := inv.Upper
+		if i < brackets {
+			upper = inv.Lower + float64(i)*width
+		}
+		fupper := fn(upper)
+
+		// If flower == y, the previous bracket already found lower.
+		if (i == 1 || flower != y) && between(y, flower, fupper) {
+			result = true
+			if !found(inv.bisect(fn, y, lower, upper, flower, fupper)) {
+				return true
+			}
+		}
+		lower, flower = upper, fupper
+	}
+	return result
+}
+
+// bisect finds x between lower and upper such that fn(x) = y.
+// flower = fn(lower) and fupper = fn(upper), and y must be between them.
+func (inv Inverse) bisect(
+	fn func(float64) float64,
+	y, lower, upper, flower, fupper float64) InverseResult {
+	if flower == y {
+		return InverseResult{X: lower}
+	}
+	if fupper == y {
+		return InverseResult{X: upper}
+	}
+	maxIterations := inv.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = kMaxIterations
+	}
+	increasing := flower < fupper
+	iterations := 0
+	for ; iterations < maxIterations; iterations++ {
+		if (upper-lower)/2.0 <= inv.Tolerance {
+			break
+		}
+		mid := (lower + upper) / 2.0
+		if mid == lower || mid == upper {
+			break
+		}
+		fmid := fn(mid)
+		if fmid == y {
+			return InverseResult{X: mid, Iterations: iterations + 1}
+		}
+		if (fmid < y) == increasing {
+			lower = mid
+		} else {
+			upper = mid
+		}
+	}
+	return InverseResult{
+		X:          (lower + upper) / 2.0,
+		Iterations: iterations,
+		Tolerance:  (upper - lower) / 2.0,
+	}
+}
+
+func (inv Inverse) outOfRange(y float64) error {
+	return fmt.Errorf(
+		"%w: no x between %v and %v gives %v",
+		ErrOutOfRange, inv.Lower, inv.Upper, y)
+}
+
+// between returns true if x is between a and b inclusive.
+func between(x, a, b float64) bool {
+	if a > b {
+		a, b = b, a
+	}
+	return a <= x && x <= b
+}
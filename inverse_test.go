@@ -0,0 +1,84 @@
+package gochart_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestApplyInverse(t *testing.T) {
+	xs := gochart.NewFloats(0.0, 1.0, 6)
+	square := func(x float64) float64 { return x * x }
+	inv := gochart.Inverse{Lower: 1.0, Upper: 2.0}
+	_, err := xs.ApplyInverse(square, inv)
+	if !errors.Is(err, gochart.ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+	inv.NaN = true
+	ys, err := xs.ApplyInverse(square, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(ys.Value(0).(float64)) {
+		t.Errorf("Expected NaN, got %v", ys.Value(0))
+	}
+	assertEqual(t, 1.0, ys.Value(1))
+	assertCloseTo(t, 1.4142, ys.Value(2).(float64))
+	assertCloseTo(t, 1.7321, ys.Value(3).(float64))
+	assertEqual(t, 2.0, ys.Value(4))
+	if !math.IsNaN(ys.Value(5).(float64)) {
+		t.Errorf("Expected NaN, got %v", ys.Value(5))
+	}
+}
+
+func TestInversePiecewise(t *testing.T) {
+	square := func(x float64) float64 { return x * x }
+	inv := gochart.Inverse{Lower: -2.0, Upper: 2.0, Brackets: 2}
+	results, err := inv.SolveAll(square, 2.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 2, len(results))
+	assertCloseTo(t, -math.Sqrt2, results[0].X)
+	assertCloseTo(t, math.Sqrt2, results[1].X)
+	result, err := inv.Solve(square, 2.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, results[0], result)
+	results, err = inv.SolveAll(square, 0.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 1, len(results))
+	assertEqual(t, 0.0, results[0].X)
+	if _, err := inv.Solve(square, -1.0); !errors.Is(
+		err, gochart.ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestInverseTolerance(t *testing.T) {
+	square := func(x float64) float64 { return x * x }
+	inv := gochart.Inverse{Lower: 0.0, Upper: 4.0, Tolerance: 0.01}
+	result, err := inv.Solve(square, 2.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.X-math.Sqrt2) > result.Tolerance {
+		t.Errorf(
+			"Expected %v within %v, got %v",
+			math.Sqrt2, result.Tolerance, result.X)
+	}
+	assertEqual(t, 8, result.Iterations)
+	assertEqual(t, 0.0078125, result.Tolerance)
+	inv = gochart.Inverse{Lower: 0.0, Upper: 4.0, MaxIterations: 3}
+	result, err = inv.Solve(square, 2.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 3, result.Iterations)
+	assertEqual(t, 0.25, result.Tolerance)
+}
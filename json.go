@@ -0,0 +1,420 @@
+package gochart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kStyleEscape matches the ANSI escape sequences that Highlight produces.
+// UnmarshalJSON accepts no other styles so that untrusted JSON cannot
+// send arbitrary escape sequences to a terminal.
+var kStyleEscape = regexp.MustCompile("^" + kAnsiEscape.String() + "$")
+
+// kMaxBlankCells is how many more cells than values UnmarshalJSON
+// allows a chart to have.
+const kMaxBlankCells = 10000
+
+// formatSpec names a built in format such as RatDecimal(3) so that
+// charts using it can be marshaled.
+type formatSpec struct {
+	Kind   string `json:"kind"`
+	Digits int    `json:"digits,omitempty"`
+}
+
+// MarshalJSON encodes this chart as JSON. The encoding holds the X and Y
+// values and the values of any Column each tagged with its type so that
+// int64, float64, *big.Int, *big.Rat, *big.Float, complex128, time.Time,
+// string, bool, int, and nil values survive exactly. The encoding also
+// holds the layout and formatting settings. Since functions cannot be
+// encoded, the encoding holds the styles that Highlight chose for each Y
+// value rather than the Highlight predicates. MarshalJSON returns an
+// error if a value has some other type or if NewChart did not create
+// this chart.
+func (c *Chart) MarshalJSON() ([]byte, error) {
+	s := c.settings
+	if s == nil {
+		return nil, errors.New("gochart: chart not created with NewChart")
+	}
+	result := chartJSON{
+		XFormat:        s.xFormat,
+		YFormat:        s.yFormat,
+		NumRows:        s.numRows,
+		NumCols:        s.numCols,
+		WrapWidth:      s.wrapWidth,
+		RuleEvery:      s.ruleEvery,
+		TransposeWidth: s.transposeWidth,
+		AlwaysStyle:    s.alwaysStyle,
+		SplitComplex:   s.splitComplex,
+		TimeLayout:     s.timeLayout,
+	}
+	var err error
+	if result.Xs, err = marshalValues(c.xs); err != nil {
+		return nil, err
+	}
+	if result.Ys, err = marshalValues(c.ys); err != nil {
+		return nil, err
+	}
+	for _, column := range s.columns {
+		values, err := marshalValues(column.values)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns = append(
+			result.Columns,
+			columnJSON{Format: column.format, Values: values})
+	}
+	for _, x := range s.rulesAt {
+		tv, err := marshalValue(x)
+		if err != nil {
+			return nil, err
+		}
+		result.RulesAt = append(result.RulesAt, tv)
+	}
+	for _, stat := range s.footer {
+		result.Footer = append(result.Footer, stat.String())
+	}
+	if s.ratFormat != nil {
+		result.RatFormat = &s.ratFormatSpec
+	}
+	if s.complexFormat != nil {
+		result.ComplexFormat = &s.complexFormatSpec
+	}
+	for i := range c.xyValues {
+		if c.xyValues[i].style != "" {
+			result.Styles = make([]string, len(c.xyValues))
+			for j := range c.xyValues {
+				result.Styles[j] = c.xyValues[j].style
+			}
+			break
+		}
+	}
+	return json.Marshal(&result)
+}
+
+// UnmarshalJSON decodes JSON that MarshalJSON produced into this chart.
+// The decoded chart is ready for WriteTo and writes exactly what the
+// original chart wrote. UnmarshalJSON returns an error for invalid input
+// such as negative layout settings or a layout with more than 10000 cells
+// beyond what the values need.
+func (c *Chart) UnmarshalJSON(data []byte) error {
+	var cj chartJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return err
+	}
+	settings := &settingsType{
+		xFormat:        cj.XFormat,
+		yFormat:        cj.YFormat,
+		numRows:        cj.NumRows,
+		numCols:        cj.NumCols,
+		wrapWidth:      cj.WrapWidth,
+		ruleEvery:      cj.RuleEvery,
+		transposeWidth: cj.TransposeWidth,
+		alwaysStyle:    cj.AlwaysStyle,
+		splitComplex:   cj.SplitComplex,
+		timeLayout:     cj.TimeLayout,
+		styles:         cj.Styles,
+	}
+	xs, err := unmarshalValues(cj.Xs)
+	if err != nil {
+		return err
+	}
+	ys, err := unmarshalValues(cj.Ys)
+	if err != nil {
+		return err
+	}
+	if xs.Len() != ys.Len() {
+		return fmt.Errorf("gochart: %d xs but %d ys", xs.Len(), ys.Len())
+	}
+	if cj.Styles != nil && len(cj.Styles) != xs.Len() {
+		return fmt.Errorf(
+			"gochart: %d xs but %d styles", xs.Len(), len(cj.Styles))
+	}
+	for _, style := range cj.Styles {
+		if style != "" && !kStyleEscape.MatchString(style) {
+			return fmt.Errorf("gochart: invalid style %q", style)
+		}
+	}
+	for _, column := range cj.Columns {
+		values, err := unmarshalValues(column.Values)
+		if err != nil {
+			return err
+		}
+		if values.Len() != xs.Len() {
+			return fmt.Errorf(
+				"gochart: %d xs but %d column values", xs.Len(), values.Len())
+		}
+		settings.columns = append(
+			settings.columns,
+			columnType{values: values, format: column.Format})
+	}
+	for _, tv := range cj.RulesAt {
+		x, err := tv.value()
+		if err != nil {
+			return err
+		}
+		settings.rulesAt = append(settings.rulesAt, x)
+	}
+	for _, name := range cj.Footer {
+		stat, err := ParseStat(name)
+		if err != nil {
+			return err
+		}
+		settings.footer = append(settings.footer, stat)
+	}
+	if cj.RatFormat != nil {
+		option, err := cj.RatFormat.ratOption()
+		if err != nil {
+			return err
+		}
+		option.mutate(settings)
+	}
+	if cj.ComplexFormat != nil {
+		option, err := cj.ComplexFormat.complexOption()
+		if err != nil {
+			return err
+		}
+		option.mutate(settings)
+	}
+	if err := checkFooter(ys, settings.footer); err != nil {
+		return err
+	}
+	if err := checkLayout(xs.Len(), settings); err != nil {
+		return err
+	}
+	*c = *newChart(xs, ys, settings)
+	return nil
+}
+
+type chartJSON struct {
+	Xs             []taggedValue `json:"xs"`
+	Ys             []taggedValue `json:"ys"`
+	Columns        []columnJSON  `json:"columns,omitempty"`
+	XFormat        string        `json:"xFormat"`
+	YFormat        string        `json:"yFormat"`
+	NumRows        int           `json:"numRows"`
+	NumCols        int           `json:"numCols"`
+	WrapWidth      int           `json:"wrapWidth,omitempty"`
+	RuleEvery      int           `json:"ruleEvery,omitempty"`
+	RulesAt        []taggedValue `json:"rulesAt,omitempty"`
+	Footer         []string      `json:"footer,omitempty"`
+	TransposeWidth int           `json:"transposeWidth,omitempty"`
+	RatFormat      *formatSpec   `json:"ratFormat,omitempty"`
+	ComplexFormat  *formatSpec   `json:"complexFormat,omitempty"`
+	SplitComplex   bool          `json:"splitComplex,omitempty"`
+	TimeLayout     string        `json:"timeLayout,omitempty"`
+	Styles         []string      `json:"styles,omitempty"`
+	AlwaysStyle    bool          `json:"alwaysStyle,omitempty"`
+}
+
+type columnJSON struct {
+	Format string        `json:"format"`
+	Values []taggedValue `json:"values"`
+}
+
+// taggedValue is a value encoded as a string along with its type. Numbers
+// are encoded as strings so that no precision is lost.
+type taggedValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+
+	// The precision of a *big.Float
+	Prec uint `json:"prec,omitempty"`
+}
+
+func marshalValues(vs Values) ([]taggedValue, error) {
+	result := make([]taggedValue, vs.Len())
+	for i := range result {
+		tv, err := marshalValue(vs.Value(i))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = tv
+	}
+	return result, nil
+}
+
+func marshalValue(v interface{}) (taggedValue, error) {
+	switch x := v.(type) {
+	case nil:
+		return taggedValue{Type: "nil"}, nil
+	case missingType:
+		return taggedValue{Type: "missing"}, nil
+	case bool:
+		return taggedValue{Type: "bool", Value: strconv.FormatBool(x)}, nil
+	case string:
+		return taggedValue{Type: "string", Value: x}, nil
+	case int:
+		return taggedValue{Type: "int", Value: strconv.Itoa(x)}, nil
+	case int64:
+		return taggedValue{
+			Type: "int64", Value: strconv.FormatInt(x, 10)}, nil
+	case float64:
+		return taggedValue{Type: "float64", Value: formatFloat(x)}, nil
+	case complex128:
+		return taggedValue{
+			Type:  "complex128",
+			Value: formatFloat(real(x)) + "," + formatFloat(imag(x))}, nil
+	case *big.Int:
+		return taggedValue{Type: "bigint", Value: x.String()}, nil
+	case *big.Rat:
+		return taggedValue{Type: "bigrat", Value: x.RatString()}, nil
+	case *big.Float:
+		return taggedValue{
+			Type: "bigfloat", Value: x.Text('g', -1), Prec: x.Prec()}, nil
+	case time.Time:
+		return taggedValue{
+			Type: "time", Value: x.Format(time.RFC3339Nano)}, nil
+	default:
+		return taggedValue{}, fmt.Errorf(
+			"gochart: cannot marshal value of type %T", v)
+	}
+}
+
+func unmarshalValues(tvs []taggedValue) (Values, error) {
+	result := make(valueSlice, len(tvs))
+	for i := range tvs {
+		v, err := tvs[i].value()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// value returns the value that t encodes.
+func (t *taggedValue) value() (interface{}, error) {
+	var result interface{}
+	var err error
+	ok := true
+	switch t.Type {
+	case "nil":
+	case "missing":
+		result = missing
+	case "bool":
+		result, err = strconv.ParseBool(t.Value)
+	case "string":
+		result = t.Value
+	case "int":
+		result, err = strconv.Atoi(t.Value)
+	case "int64":
+		result, err = strconv.ParseInt(t.Value, 10, 64)
+	case "float64":
+		result, err = strconv.ParseFloat(t.Value, 64)
+	case "complex128":
+		result, err = parseComplex(t.Value)
+	case "bigint":
+		result, ok = new(big.Int).SetString(t.Value, 10)
+	case "bigrat":
+		result, ok = new(big.Rat).SetString(t.Value)
+	case "bigfloat":
+		result, ok = new(big.Float).SetPrec(t.Prec).SetString(t.Value)
+	case "time":
+		result, err = time.Parse(time.RFC3339Nano, t.Value)
+	default:
+		return nil, fmt.Errorf("gochart: unknown value type %q", t.Type)
+	}
+	if err != nil || !ok {
+		return nil, fmt.Errorf(
+			"gochart: invalid %s value %q", t.Type, t.Value)
+	}
+	return result, nil
+}
+
+// formatFloat formats x so that strconv.ParseFloat recovers it exactly.
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+func parseComplex(s string) (complex128, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("gochart: invalid complex value %q", s)
+	}
+	re, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	im, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return complex(re, im), nil
+}
+
+// checkFooter returns an error if the footer stats cannot be computed
+// from ys rather than letting newChart panic.
+func checkFooter(ys Values, stats []Stat) (err error) {
+	if len(stats) == 0 {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gochart: cannot compute footer: %v", r)
+		}
+	}()
+	for _, stat := range stats {
+		computeStat(stat, ys)
+	}
+	return nil
+}
+
+// checkLayout returns an error if the layout settings in s are negative
+// or call for many more cells than count values need rather than letting
+// newChart panic or exhaust memory.
+func checkLayout(count int, s *settingsType) error {
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{
+		{"numRows", s.numRows},
+		{"numCols", s.numCols},
+		{"wrapWidth", s.wrapWidth},
+		{"ruleEvery", s.ruleEvery},
+		{"transposeWidth", s.transposeWidth},
+	} {
+		if setting.value < 0 {
+			return fmt.Errorf("gochart: negative %s %d", setting.name, setting.value)
+		}
+	}
+	dimensions := *s
+	dimensions.computeDimensions(count)
+	maxCells := count + kMaxBlankCells
+	rows, cols := dimensions.numRows, dimensions.numCols
+	if rows > maxCells || cols > maxCells || (rows > 0 && cols > maxCells/rows) {
+		return fmt.Errorf(
+			"gochart: %d rows and %d columns is too many for %d values",
+			rows, cols, count)
+	}
+	return nil
+}
+
+func (f *formatSpec) ratOption() (Option, error) {
+	switch f.Kind {
+	case "fraction":
+		return RatFraction(), nil
+	case "mixed":
+		return RatMixed(), nil
+	case "decimal":
+		return RatDecimal(f.Digits), nil
+	default:
+		return nil, fmt.Errorf("gochart: unknown rat format %q", f.Kind)
+	}
+}
+
+func (f *formatSpec) complexOption() (Option, error) {
+	switch f.Kind {
+	case "rect":
+		return ComplexRect(f.Digits), nil
+	case "polar":
+		return ComplexPolar(f.Digits), nil
+	default:
+		return nil, fmt.Errorf("gochart: unknown complex format %q", f.Kind)
+	}
+}
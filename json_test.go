@@ -0,0 +1,184 @@
+package gochart_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep94/gochart"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 6)
+	bigs := xs.ApplyBigInt(func(x int64, result *big.Int) *big.Int {
+		return result.Exp(big.NewInt(10), big.NewInt(20*x), nil)
+	})
+	assertJSONRoundTrip(
+		t,
+		gochart.NewChart(
+			xs,
+			bigs,
+			gochart.NumCols(2),
+			gochart.WrapWidth(30),
+			gochart.RuleEvery(2),
+			gochart.Footer(gochart.StatCount, gochart.StatMax)))
+	rats := xs.ApplyRat(func(x int64, result *big.Rat) *big.Rat {
+		return result.SetFrac64(x, 3)
+	})
+	assertJSONRoundTrip(
+		t,
+		gochart.NewChart(
+			xs,
+			rats,
+			gochart.RatMixed(),
+			gochart.RulesAt(int64(4)),
+			gochart.Footer(gochart.StatSum, gochart.StatMean)))
+	assertJSONRoundTrip(
+		t,
+		gochart.NewChart(
+			xs,
+			rats,
+			gochart.RatDecimal(3),
+			gochart.Transpose(20),
+			gochart.Column(bigs, "%d"),
+			gochart.AlwaysStyle(),
+			gochart.Highlight(
+				func(x, y interface{}) bool { return x.(int64)%2 == 0 },
+				gochart.Bold)))
+	fs := gochart.NewFloats(0, 0.5, 3)
+	assertJSONRoundTrip(
+		t,
+		gochart.NewChart(
+			fs,
+			fs.ApplyComplex(func(x float64) complex128 {
+				return complex(x, -1/x)
+			}),
+			gochart.SplitComplex(),
+			gochart.ComplexPolar(2),
+			gochart.FractionDigits(1, 3)))
+	times := gochart.NewTimes(
+		time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC), gochart.EveryDate(0, 1, 0), 3)
+	assertJSONRoundTrip(
+		t,
+		gochart.NewChart(
+			times,
+			times.Apply(func(t time.Time) float64 { return float64(t.Day()) }),
+			gochart.TimeLayout("2006-01-02")))
+}
+
+func TestJSONExact(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 1)
+	ys := xs.ApplyRat(func(x int64, result *big.Rat) *big.Rat {
+		return result.SetFrac(
+			new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(3))
+	})
+	chart := gochart.NewChart(
+		xs, ys, gochart.RatDecimal(2), gochart.Column(ys, "%v"))
+	data, err := json.Marshal(chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded gochart.Chart
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	redata, err := json.Marshal(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), string(redata))
+	expected := ys.Value(0).(*big.Rat).RatString()
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("Expected %s in %s", expected, data)
+	}
+}
+
+func TestJSONValueTypes(t *testing.T) {
+	xs := gochart.NewFloats(1, 1, 4)
+	ys := gochart.Map(xs, func(x interface{}) interface{} {
+		switch x.(float64) {
+		case 1:
+			return math.NaN()
+		case 2:
+			return big.NewFloat(1.5).SetPrec(200)
+		case 3:
+			return nil
+		default:
+			return true
+		}
+	})
+	assertJSONRoundTrip(t, gochart.NewChart(xs, ys))
+}
+
+func TestJSONErrors(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 1)
+	ys := gochart.Map(xs, func(x interface{}) interface{} {
+		return float32(1)
+	})
+	if _, err := json.Marshal(gochart.NewChart(xs, ys)); err == nil {
+		t.Error("Expected error marshaling float32")
+	}
+	if _, err := json.Marshal(&gochart.Chart{}); err == nil {
+		t.Error("Expected error marshaling zero chart")
+	}
+	for _, data := range []string{
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": []}`,
+		`{"xs": [{"type": "int32", "value": "1"}], "ys": [{"type": "nil"}]}`,
+		`{"xs": [{"type": "int64", "value": "x"}], "ys": [{"type": "nil"}]}`,
+		`{"xs": [{"type": "bigint", "value": "1.5"}], "ys": [{"type": "nil"}]}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"footer": ["median"]}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "string"}],
+			"footer": ["sum"]}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"ratFormat": {"kind": "percent"}}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"styles": []}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"columns": [{"format": "%v", "values": []}]}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"numRows": 1000000000000000, "numCols": 1}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"numRows": 1000000, "numCols": 1000000}`,
+		`{"xs": [], "ys": [], "numCols": 1000000000000000}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"numRows": -1}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"wrapWidth": -1}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"ruleEvery": -1}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"transposeWidth": -1}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"styles": ["\u001b]0;pwned\u0007\u001b[2J"]}`,
+		`{"xs": [{"type": "int64", "value": "1"}], "ys": [{"type": "nil"}],
+			"styles": ["\u001b[31m\u001b[2J"]}`,
+		`[]`,
+	} {
+		var chart gochart.Chart
+		if err := json.Unmarshal([]byte(data), &chart); err == nil {
+			t.Errorf("Expected error unmarshaling %s", data)
+		}
+	}
+}
+
+func assertJSONRoundTrip(t *testing.T, chart *gochart.Chart) {
+	t.Helper()
+	data, err := json.Marshal(chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded gochart.Chart
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+	}
+	var expected, actual strings.Builder
+	chart.WriteTo(&expected)
+	decoded.WriteTo(&actual)
+	if expected.String() != actual.String() {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected.String(), actual.String())
+	}
+}
@@ -0,0 +1,208 @@
+package gochart
+
+import (
+	"math/big"
+)
+
+const (
+	kNotANumber = "value is not an int64, float64, *big.Int, or *big.Rat"
+)
+
+// numKind classifies values for arithmetic. Kinds are ordered so that
+// combining two values yields a value of the greater kind.
+type numKind int
+
+const (
+	kindInt64 numKind = iota
+	kindBigInt
+	kindBigRat
+	kindFloat64
+)
+
+func kindOf(v interface{}) numKind {
+	switch v.(type) {
+	case int64:
+		return kindInt64
+	case *big.Int:
+		return kindBigInt
+	case *big.Rat:
+		return kindBigRat
+	case float64:
+		return kindFloat64
+	default:
+		panic(kNotANumber)
+	}
+}
+
+// isNumber returns true if v is a number that the functions in this file
+// accept.
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int64, *big.Int, *big.Rat, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// valuesEqual returns true if x and y are equal numbers or if they are
+// equal as compared by ==.
+func valuesEqual(x, y interface{}) bool {
+	if isNumber(x) && isNumber(y) {
+		return compareValues(x, y) == 0
+	}
+	return x == y
+}
+
+func toBigInt(v interface{}) *big.Int {
+	switch n := v.(type) {
+	case int64:
+		return big.NewInt(n)
+	case *big.Int:
+		return n
+	default:
+		panic(kNotANumber)
+	}
+}
+
+func toBigRat(v interface{}) *big.Rat {
+	switch n := v.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(n)
+	case *big.Int:
+		return new(big.Rat).SetInt(n)
+	case *big.Rat:
+		return n
+	default:
+		panic(kNotANumber)
+	}
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case *big.Int:
+		result, _ := new(big.Float).SetInt(n).Float64()
+		return result
+	case *big.Rat:
+		result, _ := n.Float64()
+		return result
+	case float64:
+		return n
+	default:
+		panic(kNotANumber)
+	}
+}
+
+// normalizeBigInt returns x as an int64 if it fits; otherwise it returns x.
+func normalizeBigInt(x *big.Int) interface{} {
+	if x.IsInt64() {
+		return x.Int64()
+	}
+	return x
+}
+
+// addValues returns x + y. Adding two int64 values yields a *big.Int
+// only if the sum overflows an int64.
+func addValues(x, y interface{}) interface{} {
+	return arith(
+		x,
+		y,
+		func(a, b float64) float64 { return a + b },
+		(*big.Rat).Add,
+		(*big.Int).Add)
+}
+
+// subValues returns x - y like addValues returns x + y.
+func subValues(x, y interface{}) interface{} {
+	return arith(
+		x,
+		y,
+		func(a, b float64) float64 { return a - b },
+		(*big.Rat).Sub,
+		(*big.Int).Sub)
+}
+
+// mulValues returns x * y like addValues returns x + y.
+func mulValues(x, y interface{}) interface{} {
+	return arith(
+		x,
+		y,
+		func(a, b float64) float64 { return a * b },
+		(*big.Rat).Mul,
+		(*big.Int).Mul)
+}
+
+// divValues returns x / y. If either x or y is a float64, divValues
+// returns a float64; otherwise, it returns an exact *big.Rat. divValues
+// returns nil if the quotient is exact and y is zero.
+func divValues(x, y interface{}) interface{} {
+	if maxKind(x, y) == kindFloat64 {
+		return toFloat64(x) / toFloat64(y)
+	}
+	divisor := toBigRat(y)
+	if divisor.Sign() == 0 {
+		return nil
+	}
+	return new(big.Rat).Quo(toBigRat(x), divisor)
+}
+
+func arith(
+	x, y interface{},
+	floatOp func(a, b float64) float64,
+	ratOp func(z, a, b *big.Rat) *big.Rat,
+	intOp func(z, a, b *big.Int) *big.Int) interface{} {
+	switch maxKind(x, y) {
+	case kindFloat64:
+		return floatOp(toFloat64(x), toFloat64(y))
+	case kindBigRat:
+		return ratOp(new(big.Rat), toBigRat(x), toBigRat(y))
+	case kindBigInt:
+		return intOp(new(big.Int), toBigInt(x), toBigInt(y))
+	default:
+		return normalizeBigInt(intOp(new(big.Int), toBigInt(x), toBigInt(y)))
+	}
+}
+
+// compareValues returns -1, 0, or 1 depending on whether x is less than,
+// equal to, or greater than y.
+func compareValues(x, y interface{}) int {
+	switch maxKind(x, y) {
+	case kindFloat64:
+		fx, fy := toFloat64(x), toFloat64(y)
+		if fx < fy {
+			return -1
+		}
+		if fx > fy {
+			return 1
+		}
+		return 0
+	case kindBigRat:
+		return toBigRat(x).Cmp(toBigRat(y))
+	default:
+		return toBigInt(x).Cmp(toBigInt(y))
+	}
+}
+
+// meanValue returns sum / count. If sum is exact, so is the mean: it is an
+// int64 or *big.Int when it is a whole number and a *big.Rat otherwise.
+func meanValue(sum interface{}, count int) interface{} {
+	if kindOf(sum) == kindFloat64 {
+		return toFloat64(sum) / float64(count)
+	}
+	result := new(big.Rat).SetInt64(int64(count))
+	result.Quo(toBigRat(sum), result)
+	if result.IsInt() {
+		return normalizeBigInt(result.Num())
+	}
+	return result
+}
+
+func maxKind(x, y interface{}) numKind {
+	kx, ky := kindOf(x), kindOf(y)
+	if kx > ky {
+		return kx
+	}
+	return ky
+}
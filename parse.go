@@ -0,0 +1,333 @@
+package gochart
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParsedChart is a chart read back from the text that Chart.WriteTo
+// writes. All its values are strings with surrounding spaces removed;
+// use ParseNumbers to convert them to numbers.
+type ParsedChart struct {
+
+	// The X values in order
+	Xs Values
+
+	// The first Y value for each X value
+	Ys Values
+
+	// Any other Y values for each X value such as those from Column or
+	// SplitComplex. Each element has the same length as Xs.
+	Columns []Values
+
+	// The statistics in the footer, if any
+	Footer map[Stat]string
+
+	// The number of rows and columns of the chart not counting the footer
+	NumRows int
+	NumCols int
+}
+
+// ChartParser parses charts in the text format that Chart.WriteTo writes.
+// ChartParser handles multi-column layouts, horizontal rules, footers,
+// and styled output; it does not handle wrapped or transposed charts.
+type ChartParser struct {
+
+	// The number of cells for each X value including the X cell. 0 means
+	// the shortest repeating pattern of cell widths, at least 2, which is
+	// right unless the widths within each X value themselves repeat.
+	// For example, a two column chart made with a Column option whose
+	// cells all have the same width has six cells whose widths repeat
+	// every 2 cells, so it needs CellsPerValue of 3.
+	CellsPerValue int
+
+	// If true, Parse never treats rows as a footer. Set NoFooter when
+	// X values such as min or max may follow the last horizontal rule.
+	NoFooter bool
+}
+
+// ParseChart parses a chart from r with a zero ChartParser.
+func ParseChart(r io.Reader) (*ParsedChart, error) {
+	return ChartParser{}.Parse(r)
+}
+
+// Parse reads a chart from r. The data rows of the chart may be separated
+// by horizontal rules. Unless NoFooter is true, if the X values of the
+// rows after the last rule are all statistic labels such as sum or mean,
+// Parse treats those rows as a footer, so those rows do not appear in Xs
+// even if they are data. Parse reports malformed input with errors that give the
+// offending line number.
+func (p ChartParser) Parse(r io.Reader) (*ParsedChart, error) {
+	scanner := bufio.NewScanner(r)
+	var border string
+	var widths []int
+	var sections [][][]string
+	var current [][]string
+	lineNo := 0
+	ended := false
+	for scanner.Scan() {
+		lineNo++
+		line := kAnsiEscape.ReplaceAllString(scanner.Text(), "")
+		blank := strings.TrimSpace(line) == ""
+		switch {
+		case ended:
+			if !blank {
+				return nil, parseError(lineNo, "text after end of chart")
+			}
+		case border == "":
+			if blank {
+				continue
+			}
+			var err error
+			if widths, err = parseBorder(line); err != nil {
+				return nil, parseError(lineNo, err.Error())
+			}
+			border = line
+		case blank:
+			if len(current) > 0 {
+				return nil, parseError(lineNo, "missing bottom border")
+			}
+			ended = true
+		case line == border:
+			if len(current) > 0 {
+				sections = append(sections, current)
+				current = nil
+			}
+		default:
+			cells, err := splitRow(line, widths)
+			if err != nil {
+				return nil, parseError(lineNo, err.Error())
+			}
+			current = append(current, cells)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if border == "" {
+		return nil, fmt.Errorf("gochart: no chart found")
+	}
+	if len(current) > 0 {
+		return nil, parseError(lineNo, "missing bottom border")
+	}
+	cellsPerValue := p.CellsPerValue
+	if cellsPerValue <= 0 {
+		cellsPerValue = repeatingPeriod(widths)
+	}
+	if len(widths)%cellsPerValue != 0 {
+		return nil, fmt.Errorf(
+			"gochart: %d cells per row is not a multiple of %d",
+			len(widths), cellsPerValue)
+	}
+	return buildParsedChart(
+		sections, len(widths)/cellsPerValue, cellsPerValue, !p.NoFooter), nil
+}
+
+// ParseNumbers converts the strings in vs to numbers. Integers become
+// int64 or *big.Int values if they do not fit in an int64; fractions like
+// 5/2 and mixed numbers like 2 1/2 become *big.Rat values; and other
+// numbers become float64 values. Empty strings and <nil> become nil.
+// ParseNumbers returns an error if some string is not a number.
+func ParseNumbers(vs Values) (Values, error) {
+	result := make(valueSlice, vs.Len())
+	for i := range result {
+		s, ok := vs.Value(i).(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				"gochart: value %d is a %T, not a string", i, vs.Value(i))
+		}
+		number, err := parseNumber(s)
+		if err != nil {
+			return nil, fmt.Errorf("gochart: value %d: %v", i, err)
+		}
+		result[i] = number
+	}
+	return result, nil
+}
+
+var kAnsiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func parseError(lineNo int, msg string) error {
+	return fmt.Errorf("gochart: line %d: %s", lineNo, msg)
+}
+
+// parseBorder returns the cell widths of a border like +---+--+.
+func parseBorder(line string) ([]int, error) {
+	if len(line) < 2 || line[0] != '+' || line[len(line)-1] != '+' {
+		return nil, fmt.Errorf("expected border like +---+, got %q", line)
+	}
+	var result []int
+	for _, dashes := range strings.Split(line[1:len(line)-1], "+") {
+		if strings.Trim(dashes, "-") != "" {
+			return nil, fmt.Errorf("expected border like +---+, got %q", line)
+		}
+		result = append(result, len(dashes))
+	}
+	return result, nil
+}
+
+// splitRow splits a row like |  1|  2| into cells of the given widths
+// with spaces trimmed.
+func splitRow(line string, widths []int) ([]string, error) {
+	expectedWidth := len(widths) + 1
+	for _, width := range widths {
+		expectedWidth += width
+	}
+	if utf8.RuneCountInString(line) != expectedWidth {
+		return nil, fmt.Errorf(
+			"expected row %d characters wide, got %q",
+			expectedWidth, line)
+	}
+	runes := []rune(line)
+	result := make([]string, len(widths))
+	pos := 0
+	for i, width := range widths {
+		if runes[pos] != '|' {
+			return nil, fmt.Errorf(
+				"expected | at position %d, got %q", pos+1, line)
+		}
+		result[i] = strings.TrimSpace(string(runes[pos+1 : pos+1+width]))
+		pos += width + 1
+	}
+	if runes[pos] != '|' {
+		return nil, fmt.Errorf("expected | at end of %q", line)
+	}
+	return result, nil
+}
+
+// repeatingPeriod returns the length of the shortest pattern of at least
+// 2 widths that repeats to make widths.
+func repeatingPeriod(widths []int) int {
+	for period := 2; period < len(widths); period++ {
+		if len(widths)%period != 0 {
+			continue
+		}
+		repeats := true
+		for i := period; i < len(widths) && repeats; i++ {
+			repeats = widths[i] == widths[i-period]
+		}
+		if repeats {
+			return period
+		}
+	}
+	return len(widths)
+}
+
+func buildParsedChart(
+	sections [][][]string,
+	numCols, cellsPerValue int,
+	detectFooter bool) *ParsedChart {
+	result := &ParsedChart{NumCols: numCols}
+	if detectFooter && len(sections) > 1 {
+		if footer, ok := parseFooter(
+			sections[len(sections)-1], numCols, cellsPerValue); ok {
+			result.Footer = footer
+			sections = sections[:len(sections)-1]
+		}
+	}
+	var rows [][]string
+	for _, section := range sections {
+		rows = append(rows, section...)
+	}
+	result.NumRows = len(rows)
+	xs := make(valueSlice, 0, len(rows)*numCols)
+	ys := make(valueSlice, 0, len(rows)*numCols)
+	columns := make([]valueSlice, cellsPerValue-2)
+	for col := 0; col < numCols; col++ {
+		for _, row := range rows {
+			cells := row[col*cellsPerValue : (col+1)*cellsPerValue]
+			if isBlank(cells) {
+				continue
+			}
+			xs = append(xs, cells[0])
+			ys = append(ys, cells[1])
+			for i := range columns {
+				columns[i] = append(columns[i], cells[i+2])
+			}
+		}
+	}
+	result.Xs = xs
+	result.Ys = ys
+	for _, column := range columns {
+		result.Columns = append(result.Columns, column)
+	}
+	return result
+}
+
+// parseFooter returns the statistics in rows if rows is a footer.
+func parseFooter(
+	rows [][]string, numCols, cellsPerValue int) (map[Stat]string, bool) {
+	result := make(map[Stat]string)
+	for _, row := range rows {
+		for col := 0; col < numCols; col++ {
+			cells := row[col*cellsPerValue : (col+1)*cellsPerValue]
+			if isBlank(cells) {
+				continue
+			}
+			stat, err := ParseStat(cells[0])
+			if err != nil {
+				return nil, false
+			}
+			result[stat] = cells[1]
+		}
+	}
+	return result, len(result) > 0
+}
+
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNumber(s string) (interface{}, error) {
+	if s == "" || s == "<nil>" {
+		return nil, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	if b, ok := new(big.Int).SetString(s, 10); ok {
+		return b, nil
+	}
+	if strings.Contains(s, "/") {
+		return parseRat(s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	return f, nil
+}
+
+// parseRat parses fractions like -5/2 and mixed numbers like -2 1/2.
+func parseRat(s string) (*big.Rat, error) {
+	whole, frac := "", s
+	if idx := strings.IndexByte(s, ' '); idx != -1 {
+		whole, frac = s[:idx], strings.TrimSpace(s[idx+1:])
+	}
+	result, ok := new(big.Rat).SetString(frac)
+	if !ok || (whole != "" && (result.Sign() < 0 || strings.Contains(whole, "/"))) {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	if whole == "" {
+		return result, nil
+	}
+	w, ok := new(big.Rat).SetString(whole)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	if w.Sign() < 0 || strings.HasPrefix(whole, "-") {
+		result.Neg(result)
+	}
+	return result.Add(result, w), nil
+}
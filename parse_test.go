@@ -0,0 +1,203 @@
+package gochart_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/keep94/gochart"
+	"github.com/keep94/gomath"
+)
+
+func TestParseChartRoundTrip(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 100)
+	ys := xs.ApplyBigInt(gomath.NewPartition().Chart)
+	parsed := parseChart(
+		t,
+		gochart.ChartParser{},
+		gochart.NewChart(xs, ys, gochart.NumRows(25), gochart.RuleEvery(10)))
+	assertEqual(t, 25, parsed.NumRows)
+	assertEqual(t, 4, parsed.NumCols)
+	assertEqual(t, 0, len(parsed.Columns))
+	assertEqual(t, 0, len(parsed.Footer))
+	assertSprintValuesEqual(t, parsed.Xs, sprintValues(xs)...)
+	assertSprintValuesEqual(t, parsed.Ys, sprintValues(ys)...)
+	numbers, err := gochart.ParseNumbers(parsed.Ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, int64(190569292), numbers.Value(99))
+}
+
+func TestParseChartFooterAndColumns(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 5)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	cubes := xs.Apply(func(x int64) int64 { return x * x * x })
+	chart := gochart.NewChart(
+		xs,
+		ys,
+		gochart.NumCols(2),
+		gochart.Column(cubes, "%d"),
+		gochart.Footer(gochart.StatSum, gochart.StatMean, gochart.StatMax),
+		gochart.AlwaysStyle(),
+		gochart.Highlight(
+			func(x, y interface{}) bool { return y.(int64) > 10 }, gochart.Red))
+	parsed := parseChart(t, gochart.ChartParser{}, chart)
+	assertEqual(t, 3, parsed.NumRows)
+	assertEqual(t, 2, parsed.NumCols)
+	assertSprintValuesEqual(t, parsed.Xs, "1", "2", "3", "4", "5")
+	assertSprintValuesEqual(t, parsed.Ys, "1", "4", "9", "16", "25")
+	assertEqual(t, 1, len(parsed.Columns))
+	assertSprintValuesEqual(t, parsed.Columns[0], "1", "8", "27", "64", "125")
+	assertEqual(t, 3, len(parsed.Footer))
+	assertEqual(t, "55", parsed.Footer[gochart.StatSum])
+	assertEqual(t, "11", parsed.Footer[gochart.StatMean])
+	assertEqual(t, "25", parsed.Footer[gochart.StatMax])
+}
+
+func TestParseChartCellsPerValue(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	chart := gochart.NewChart(
+		xs, xs, gochart.Column(xs, "%d"), gochart.Column(xs, "%d"))
+
+	// All four cells have width 1 so they look like two columns.
+	parsed := parseChart(t, gochart.ChartParser{}, chart)
+	assertEqual(t, 2, parsed.NumCols)
+	parsed = parseChart(t, gochart.ChartParser{CellsPerValue: 4}, chart)
+	assertEqual(t, 1, parsed.NumCols)
+	assertEqual(t, 2, len(parsed.Columns))
+	assertSprintValuesEqual(t, parsed.Columns[1], "1", "2", "3")
+	_, err := gochart.ChartParser{CellsPerValue: 3}.Parse(
+		strings.NewReader("+-+-+-+-+\n|1|1|1|1|\n+-+-+-+-+\n"))
+	if err == nil {
+		t.Error("Expected error for 4 cells with 3 cells per value")
+	}
+}
+
+func TestParseChartCellsPerValueTwoColumns(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 4)
+	chart := gochart.NewChart(
+		xs, xs, gochart.Column(xs, "%d"), gochart.NumCols(2))
+
+	// All six cells have width 1 so they repeat every 2 cells.
+	parsed := parseChart(t, gochart.ChartParser{}, chart)
+	assertEqual(t, 3, parsed.NumCols)
+	parsed = parseChart(t, gochart.ChartParser{CellsPerValue: 3}, chart)
+	assertEqual(t, 2, parsed.NumCols)
+	assertSprintValuesEqual(t, parsed.Columns[0], "1", "2", "3", "4")
+}
+
+func TestParseChartNoFooter(t *testing.T) {
+	text := "+---+-+\n|  1|1|\n+---+-+\n|max|2|\n+---+-+\n"
+	parsed, err := gochart.ParseChart(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSprintValuesEqual(t, parsed.Xs, "1")
+	assertEqual(t, "2", parsed.Footer[gochart.StatMax])
+	parsed, err = gochart.ChartParser{NoFooter: true}.Parse(
+		strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSprintValuesEqual(t, parsed.Xs, "1", "max")
+	assertSprintValuesEqual(t, parsed.Ys, "1", "2")
+	assertEqual(t, 0, len(parsed.Footer))
+}
+
+func TestParseChartEmptyAndBlankLines(t *testing.T) {
+	parsed, err := gochart.ParseChart(strings.NewReader(
+		"\n+-+--+\n| 1| a|\n|22|bb|\n+-+--+\n\n\n"))
+	if err == nil {
+		t.Fatalf("Expected error, got %v", parsed)
+	}
+	parsed, err = gochart.ParseChart(strings.NewReader(
+		"\n+--+--+\n| 1| a|\n|22|bb|\n+--+--+\n\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSprintValuesEqual(t, parsed.Xs, "1", "22")
+	assertSprintValuesEqual(t, parsed.Ys, "a", "bb")
+}
+
+func TestParseChartErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"\n\n",
+		"|1|2|\n",
+		"+--+-x+\n",
+		"+-+-+\n|1|2|\n",
+		"+-+-+\n|1|2|\n+-+-+\nextra\n",
+		"+-+-+\n|1|22|\n+-+-+\n",
+		"+-+-+\n|1 2|\n+-+-+\n",
+		"+-+-+\n|1|2|\n\n+-+-+\n",
+	} {
+		if _, err := gochart.ParseChart(strings.NewReader(text)); err == nil {
+			t.Errorf("Expected error parsing %q", text)
+		}
+	}
+}
+
+func TestParseChartErrorLine(t *testing.T) {
+	_, err := gochart.ParseChart(strings.NewReader("+-+-+\n|1|2|\n|3|4\n+-+-+\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected error on line 3, got %v", err)
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 8)
+	values, err := gochart.ParseNumbers(stringValues(
+		xs,
+		"42",
+		"123456789012345678901234567890",
+		"5/2",
+		"2 1/2",
+		"-2 1/2",
+		"1.5e3",
+		"",
+		"<nil>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, int64(42), values.Value(0))
+	assertEqual(t, "123456789012345678901234567890", values.Value(1).(*big.Int).String())
+	assertEqual(t, "5/2", values.Value(2).(*big.Rat).RatString())
+	assertEqual(t, "5/2", values.Value(3).(*big.Rat).RatString())
+	assertEqual(t, "-5/2", values.Value(4).(*big.Rat).RatString())
+	assertEqual(t, 1500.0, values.Value(5))
+	assertEqual(t, nil, values.Value(6))
+	assertEqual(t, nil, values.Value(7))
+	for _, s := range []string{"abc", "1/x", "2 -1/2", "1 2"} {
+		_, err := gochart.ParseNumbers(stringValues(gochart.NewInts(1, 1, 1), s))
+		if err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+	if _, err := gochart.ParseNumbers(xs); err == nil {
+		t.Error("Expected error parsing non strings")
+	}
+}
+
+func parseChart(
+	t *testing.T,
+	parser gochart.ChartParser,
+	chart *gochart.Chart) *gochart.ParsedChart {
+	t.Helper()
+	var builder strings.Builder
+	chart.WriteTo(&builder)
+	result, err := parser.Parse(strings.NewReader(builder.String()))
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n%s", err, builder.String())
+	}
+	return result
+}
+
+func sprintValues(vs gochart.Values) []string {
+	result := make([]string, vs.Len())
+	for i := range result {
+		result[i] = fmt.Sprint(vs.Value(i))
+	}
+	return result
+}
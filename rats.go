@@ -0,0 +1,64 @@
+package gochart
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ApplyRat applies f to each of these X values and returns the resulting
+// Y values as exact fractions. f must store the result in result and
+// return result. By default, *big.Rat values show as fractions like 5/2
+// and 3/1; use RatFraction, RatMixed, or RatDecimal to change this.
+func (i *Ints) ApplyRat(f func(x int64, result *big.Rat) *big.Rat) Values {
+	result := make(valueSlice, i.count)
+	for j := 0; j < i.count; j++ {
+		result[j] = f(i.value(j), new(big.Rat))
+	}
+	return result
+}
+
+// RatFraction shows *big.Rat values as fractions in lowest terms like
+// 5/2 or -1/3. Whole numbers show without a denominator.
+// RatFraction overrides XFormat and YFormat for *big.Rat values.
+func RatFraction() Option {
+	return ratFormat(formatSpec{Kind: "fraction"}, func(r *big.Rat) string {
+		return r.RatString()
+	})
+}
+
+// RatMixed shows *big.Rat values as mixed numbers like 2 1/2 or -1 1/3.
+// Whole numbers show without a fraction part, and values between -1 and 1
+// show without a whole part. RatMixed overrides XFormat and YFormat for
+// *big.Rat values.
+func RatMixed() Option {
+	return ratFormat(formatSpec{Kind: "mixed"}, formatMixed)
+}
+
+// RatDecimal shows *big.Rat values as decimals rounded to digits digits
+// after the decimal point. RatDecimal overrides XFormat and YFormat for
+// *big.Rat values.
+func RatDecimal(digits int) Option {
+	spec := formatSpec{Kind: "decimal", Digits: digits}
+	return ratFormat(spec, func(r *big.Rat) string {
+		return r.FloatString(digits)
+	})
+}
+
+func ratFormat(spec formatSpec, f func(r *big.Rat) string) Option {
+	return optionFunc(func(s *settingsType) {
+		s.ratFormat = f
+		s.ratFormatSpec = spec
+	})
+}
+
+func formatMixed(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	whole, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	fraction := new(big.Rat).SetFrac(rem.Abs(rem), r.Denom())
+	if whole.Sign() == 0 {
+		return r.RatString()
+	}
+	return fmt.Sprintf("%v %v", whole, fraction.RatString())
+}
@@ -0,0 +1,349 @@
+package gochart
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/keep94/gomath"
+)
+
+// Sequence is an integer sequence indexed by X value.
+type Sequence interface {
+
+	// Apply returns the value of this sequence at each of the X values in
+	// xs. Sequences backed by streams require X values that are greater
+	// than 0 and ascending just like Ints.ApplyStream.
+	Apply(xs *Ints) Values
+}
+
+// SequenceFunc adapts an ordinary function to a Sequence.
+type SequenceFunc func(xs *Ints) Values
+
+// Apply returns f(xs).
+func (f SequenceFunc) Apply(xs *Ints) Values {
+	return f(xs)
+}
+
+// SequenceRegistry maps names to Sequences so that callers can request a
+// Sequence by a name such as "partitions" or "ugly(3,5,7)". A
+// SequenceRegistry is safe to use from multiple goroutines.
+type SequenceRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]sequenceEntry
+}
+
+// NewSequenceRegistry returns a new, empty SequenceRegistry.
+func NewSequenceRegistry() *SequenceRegistry {
+	return &SequenceRegistry{entries: make(map[string]sequenceEntry)}
+}
+
+// Register registers a Sequence under name. name must consist of letters,
+// digits, and underscores. usage shows how to call the sequence, e.g.
+// "ugly(p1, p2, ...)", followed by a brief description. Lookup calls
+// factory with the parameters in parentheses after the name, or with no
+// parameters if there are no parentheses. factory returns an error if the
+// parameters are not valid. Register panics if name is invalid or already
+// registered.
+func (r *SequenceRegistry) Register(
+	name, usage string, factory func(params []int64) (Sequence, error)) {
+	if !isSequenceName(name) {
+		panic(fmt.Sprintf("gochart: invalid sequence name %q", name))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		panic(fmt.Sprintf("gochart: sequence %q already registered", name))
+	}
+	r.entries[name] = sequenceEntry{usage: usage, factory: factory}
+}
+
+// Lookup returns the Sequence that spec names. spec is a registered name
+// optionally followed by comma separated integer parameters in
+// parentheses such as "ugly(3, 5, 7)".
+func (r *SequenceRegistry) Lookup(spec string) (Sequence, error) {
+	name, params, err := parseSequenceSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	entry, ok := r.entries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("gochart: unknown sequence %q", name)
+	}
+	result, err := entry.factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("gochart: %s: %v", strings.TrimSpace(spec), err)
+	}
+	return result, nil
+}
+
+// Names returns the registered names in sorted order.
+func (r *SequenceRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Usage returns the usage of the sequence registered under name or the
+// empty string if there is no such sequence.
+func (r *SequenceRegistry) Usage(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.entries[name].usage
+}
+
+const (
+	// kMaxFactorsN is the largest n that factors accepts. Finding the
+	// divisors of n takes time proportional to the square root of n.
+	kMaxFactorsN = 100000000000000
+
+	// kMaxProducts bounds the number of products that products computes
+	// up front.
+	kMaxProducts = 1000000
+
+	// kMaxCakeDimension is the largest n that cake accepts. cake keeps a
+	// table with n+1 rows.
+	kMaxCakeDimension = 1000
+)
+
+// Sequences holds the sequences of the github.com/keep94/gomath package
+// under the names cake, decadeprimes, factors, fibonacci, happys,
+// harshads, partitions, primes, products, and ugly. Usage describes each
+// one. Sequences backed by a list of values such as factors give 0 for X
+// values past the end of the list just as Ints.ApplyStream does when its
+// stream runs out. Lookup rejects parameters of factors, products, and
+// cake that would take too long or too much memory to compute up front.
+// Callers may register their own sequences here too.
+var Sequences = newDefaultSequences()
+
+func newDefaultSequences() *SequenceRegistry {
+	r := NewSequenceRegistry()
+	r.Register(
+		"cake",
+		"cake(n): the most pieces from x cuts of an n dimensional cake",
+		func(params []int64) (Sequence, error) {
+			if err := checkParams(params, 1, 1); err != nil {
+				return nil, err
+			}
+			n := params[0]
+			if n < 0 || n > kMaxCakeDimension {
+				return nil, fmt.Errorf(
+					"n must be between 0 and %d", kMaxCakeDimension)
+			}
+			return SequenceFunc(func(xs *Ints) Values {
+				cake := gomath.NewCake()
+				return xs.ApplyBigInt(func(x int64, result *big.Int) *big.Int {
+					return cake.Eval(int(n), int(x), result)
+				})
+			}), nil
+		})
+	r.Register(
+		"decadeprimes",
+		"decadeprimes(start): the xth k >= start such that 10k+1, 10k+3, "+
+			"10k+7, and 10k+9 are all prime, default 1",
+		intStreamFactory(gomath.DecadePrimes))
+	r.Register(
+		"factors",
+		"factors(n): the xth smallest divisor of n",
+		func(params []int64) (Sequence, error) {
+			if err := checkParams(params, 1, 1); err != nil {
+				return nil, err
+			}
+			if params[0] < 1 || params[0] > kMaxFactorsN {
+				return nil, fmt.Errorf("n must be between 1 and %d", kMaxFactorsN)
+			}
+			return sliceSequence(gomath.Factors(params[0])), nil
+		})
+	r.Register(
+		"fibonacci",
+		"fibonacci(a, b): the xth term of a, b, a+b, ..., default 1, 1",
+		func(params []int64) (Sequence, error) {
+			if len(params) == 0 {
+				params = []int64{1, 1}
+			}
+			if err := checkParams(params, 2, 2); err != nil {
+				return nil, err
+			}
+			return SequenceFunc(func(xs *Ints) Values {
+				return xs.ApplyBigIntStream(gomath.Fibonacci(params[0], params[1]))
+			}), nil
+		})
+	r.Register(
+		"happys",
+		"happys(start): the xth happy number >= start, default 1",
+		intStreamFactory(gomath.Happys))
+	r.Register(
+		"harshads",
+		"harshads(start): the xth harshad number >= start, default 1",
+		intStreamFactory(gomath.Harshads))
+	r.Register(
+		"partitions",
+		"partitions: the number of ways to write x as a sum of positive "+
+			"integers",
+		func(params []int64) (Sequence, error) {
+			if err := checkParams(params, 0, 0); err != nil {
+				return nil, err
+			}
+			return SequenceFunc(func(xs *Ints) Values {
+				return xs.ApplyBigInt(gomath.NewPartition().Chart)
+			}), nil
+		})
+	r.Register(
+		"primes",
+		"primes(start): the xth prime >= start, default 1",
+		intStreamFactory(gomath.Primes))
+	r.Register(
+		"products",
+		"products(n, count): the xth smallest product of count integers "+
+			"from 1 to n",
+		func(params []int64) (Sequence, error) {
+			if err := checkParams(params, 2, 2); err != nil {
+				return nil, err
+			}
+			if params[0] < 1 || params[1] < 0 {
+				return nil, fmt.Errorf(
+					"n must be positive and count must be non negative")
+			}
+			if !fewProducts(params[0], params[1]) {
+				return nil, fmt.Errorf(
+					"products(%d, %d) has too many products to compute",
+					params[0], params[1])
+			}
+			return sliceSequence(
+				gomath.ProductsSlice(int(params[0]), int(params[1]))), nil
+		})
+	r.Register(
+		"ugly",
+		"ugly(p1, p2, ...): the xth number whose prime factors are among "+
+			"p1, p2, ...",
+		func(params []int64) (Sequence, error) {
+			if len(params) == 0 {
+				return nil, fmt.Errorf("need at least one prime factor")
+			}
+			for _, p := range params {
+				if p < 2 {
+					return nil, fmt.Errorf("prime factors must be at least 2")
+				}
+			}
+			return SequenceFunc(func(xs *Ints) Values {
+				return xs.ApplyBigIntStream(gomath.Ugly(params...))
+			}), nil
+		})
+	return r
+}
+
+type sequenceEntry struct {
+	usage   string
+	factory func(params []int64) (Sequence, error)
+}
+
+// intStreamFactory returns a factory for a stream that takes an optional
+// start parameter which defaults to 1.
+func intStreamFactory(
+	newStream func(start int64) gomath.IntStream,
+) func(params []int64) (Sequence, error) {
+	return func(params []int64) (Sequence, error) {
+		if err := checkParams(params, 0, 1); err != nil {
+			return nil, err
+		}
+		start := int64(1)
+		if len(params) == 1 {
+			start = params[0]
+		}
+		return SequenceFunc(func(xs *Ints) Values {
+			return xs.ApplyStream(newStream(start))
+		}), nil
+	}
+}
+
+// fewProducts reports whether there are at most kMaxProducts ways to
+// choose count integers from 1 to n with repetition, n choose count with
+// repetition being an upper bound on the number of distinct products.
+func fewProducts(n, count int64) bool {
+	if n > kMaxProducts || count > kMaxProducts {
+		return false
+	}
+	ways := int64(1)
+	for i := int64(1); i <= count; i++ {
+		ways = ways * (n - 1 + i) / i
+		if ways > kMaxProducts {
+			return false
+		}
+	}
+	return true
+}
+
+// sliceSequence returns a Sequence whose xth value is s[x-1] or 0 if
+// there is no such value.
+func sliceSequence(s []int64) Sequence {
+	return SequenceFunc(func(xs *Ints) Values {
+		return xs.Apply(func(x int64) int64 {
+			if x < 1 || x > int64(len(s)) {
+				return 0
+			}
+			return s[x-1]
+		})
+	})
+}
+
+func checkParams(params []int64, min, max int) error {
+	if len(params) < min || len(params) > max {
+		if min == max {
+			return fmt.Errorf("need %d parameters, got %d", min, len(params))
+		}
+		return fmt.Errorf(
+			"need %d to %d parameters, got %d", min, max, len(params))
+	}
+	return nil
+}
+
+// parseSequenceSpec splits a spec such as "ugly(3, 5, 7)" into its name
+// and parameters.
+func parseSequenceSpec(spec string) (name string, params []int64, err error) {
+	spec = strings.TrimSpace(spec)
+	name = spec
+	if idx := strings.IndexByte(spec, '('); idx != -1 {
+		if !strings.HasSuffix(spec, ")") {
+			return "", nil, fmt.Errorf("gochart: missing ) in %q", spec)
+		}
+		name = strings.TrimSpace(spec[:idx])
+		paramStr := strings.TrimSpace(spec[idx+1 : len(spec)-1])
+		if paramStr != "" {
+			for _, field := range strings.Split(paramStr, ",") {
+				param, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+				if err != nil {
+					return "", nil, fmt.Errorf(
+						"gochart: invalid parameter %q in %q", field, spec)
+				}
+				params = append(params, param)
+			}
+		}
+	}
+	if !isSequenceName(name) {
+		return "", nil, fmt.Errorf("gochart: invalid sequence %q", spec)
+	}
+	return name, params, nil
+}
+
+func isSequenceName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
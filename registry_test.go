@@ -0,0 +1,85 @@
+package gochart_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestSequences(t *testing.T) {
+	assertSequence(t, "partitions", 1, 5, "1", "2", "3", "5", "7")
+	assertSequence(t, " ugly( 3, 5,7 ) ", 1, 6, "1", "3", "5", "7", "9", "15")
+	assertSequence(t, "primes", 1, 5, "2", "3", "5", "7", "11")
+	assertSequence(t, "primes(100)", 1, 3, "101", "103", "107")
+	assertSequence(t, "harshads()", 10, 3, "10", "12", "18")
+	assertSequence(t, "happys", 1, 4, "1", "7", "10", "13")
+	assertSequence(t, "fibonacci", 1, 6, "1", "1", "2", "3", "5", "8")
+	assertSequence(t, "fibonacci(2, 1)", 1, 5, "2", "1", "3", "4", "7")
+	assertSequence(t, "factors(12)", 4, 4, "4", "6", "12", "0")
+	assertSequence(t, "products(3, 2)", 1, 6, "1", "2", "3", "4", "6", "9")
+	assertSequence(t, "cake(2)", 0, 5, "1", "2", "4", "7", "11")
+	assertSequence(t, "decadeprimes", 1, 2, "1", "10")
+}
+
+func TestSequenceApplyTwice(t *testing.T) {
+	seq, err := gochart.Sequences.Lookup("ugly(2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xs := gochart.NewInts(1, 1, 3)
+	assertSprintValuesEqual(t, seq.Apply(xs), "1", "2", "4")
+	assertSprintValuesEqual(t, seq.Apply(xs), "1", "2", "4")
+}
+
+func TestSequenceLookupErrors(t *testing.T) {
+	for _, spec := range []string{
+		"", "nosuch", "primes(", "primes(x)", "primes(1, 2)", "ugly",
+		"ugly(1)", "factors(0)", "partitions(3)", "fibonacci(1)",
+		"products(0, 1)", "cake(-1)", "pri mes", "primes(1))",
+		"factors(9223372036854775783)", "products(100, 10)",
+		"products(1, 1000000000000)", "cake(1000000)"} {
+		if _, err := gochart.Sequences.Lookup(spec); err == nil {
+			t.Errorf("Expected error looking up %q", spec)
+		}
+	}
+}
+
+func TestSequenceRegistry(t *testing.T) {
+	r := gochart.NewSequenceRegistry()
+	r.Register(
+		"multiples",
+		"multiples(n): x times n",
+		func(params []int64) (gochart.Sequence, error) {
+			if len(params) != 1 {
+				return nil, fmt.Errorf("need n")
+			}
+			return gochart.SequenceFunc(func(xs *gochart.Ints) gochart.Values {
+				return xs.Apply(func(x int64) int64 { return x * params[0] })
+			}), nil
+		})
+	seq, err := r.Lookup("multiples(3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSprintValuesEqual(t, seq.Apply(gochart.NewInts(1, 1, 3)), "3", "6", "9")
+	assertEqual(t, "[multiples]", fmt.Sprint(r.Names()))
+	assertEqual(t, "multiples(n): x times n", r.Usage("multiples"))
+	assertEqual(t, "", r.Usage("nosuch"))
+	assertPanic(t, func() {
+		r.Register("multiples", "", nil)
+	})
+	assertPanic(t, func() {
+		r.Register("bad name", "", nil)
+	})
+}
+
+func assertSequence(
+	t *testing.T, spec string, start int64, count int, expected ...string) {
+	t.Helper()
+	seq, err := gochart.Sequences.Lookup(spec)
+	if err != nil {
+		t.Fatalf("Lookup(%q) failed: %v", spec, err)
+	}
+	assertSprintValuesEqual(t, seq.Apply(gochart.NewInts(start, 1, count)), expected...)
+}
@@ -0,0 +1,136 @@
+package gochart
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Stat is a statistic computed over the Y values of a chart.
+type Stat int
+
+const (
+	// StatCount is the number of Y values.
+	StatCount Stat = iota
+
+	// StatMin is the smallest Y value.
+	StatMin
+
+	// StatMax is the largest Y value.
+	StatMax
+
+	// StatSum is the sum of the Y values.
+	StatSum
+
+	// StatMean is the arithmetic mean of the Y values.
+	StatMean
+)
+
+var statNames = []string{"count", "min", "max", "sum", "mean"}
+
+// String returns the label shown for s in a chart footer.
+func (s Stat) String() string {
+	if s < 0 || int(s) >= len(statNames) {
+		return fmt.Sprintf("Stat(%d)", int(s))
+	}
+	return statNames[s]
+}
+
+// ParseStat returns the Stat whose String method returns name.
+func ParseStat(name string) (Stat, error) {
+	for i, statName := range statNames {
+		if name == statName {
+			return Stat(i), nil
+		}
+	}
+	return 0, fmt.Errorf("gochart: unknown stat %q", name)
+}
+
+// Footer appends a footer to the chart, below a horizontal rule, showing
+// the given statistics of the Y values. Each statistic appears with its
+// label in an X cell and its value in the matching Y cell, filling the
+// columns of the chart from left to right. The Y values must be int64,
+// float64, *big.Int, or *big.Rat or else NewChart panics. Statistics of
+// values other than float64 are exact: sums never overflow, and a mean
+// that is not a whole number is a *big.Rat shown as a fraction unless
+// RatMixed or RatDecimal say otherwise. The count is always shown in
+// decimal; the other statistics use the Y format and are blank if there
+// are no Y values.
+func Footer(stats ...Stat) Option {
+	return optionFunc(func(s *settingsType) {
+		s.footer = append(s.footer, stats...)
+	})
+}
+
+func createFooter(ys Values, s *settingsType) xyValuesType {
+	result := make(xyValuesType, len(s.footer))
+	for i, stat := range s.footer {
+		result[i].x = stat.String()
+		result[i].ys = []string{formatStat(computeStat(stat, ys), s)}
+	}
+	return result
+}
+
+// computeStat returns stat over ys. It returns nil for any stat other
+// than StatCount if ys is empty since there is no Y value to tell what
+// type the result should be.
+func computeStat(stat Stat, ys Values) interface{} {
+	if stat < StatCount || stat > StatMean {
+		panic(fmt.Sprintf("unknown stat %v", stat))
+	}
+	values := presentValues(ys)
+	count := len(values)
+	if stat == StatCount {
+		return count
+	}
+	if count == 0 {
+		return nil
+	}
+	result := values[0]
+
+	// Panics if ys holds only one value and it is not a number.
+	kindOf(result)
+	for _, y := range values[1:] {
+		switch stat {
+		case StatMin:
+			if compareValues(y, result) < 0 {
+				result = y
+			}
+		case StatMax:
+			if compareValues(y, result) > 0 {
+				result = y
+			}
+		default:
+			result = addValues(result, y)
+		}
+	}
+	if stat == StatMean {
+		return meanValue(result, count)
+	}
+	return result
+}
+
+// presentValues returns the values in ys skipping the missing values of
+// a comparison.
+func presentValues(ys Values) []interface{} {
+	result := make([]interface{}, 0, ys.Len())
+	for i := 0; i < ys.Len(); i++ {
+		if y := ys.Value(i); y != missing {
+			result = append(result, y)
+		}
+	}
+	return result
+}
+
+func formatStat(value interface{}, s *settingsType) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case int:
+		return fmt.Sprint(v)
+	case *big.Rat:
+		if s.ratFormat == nil {
+			return v.RatString()
+		}
+	}
+	return s.format(s.yFormat, value)
+}
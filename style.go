@@ -0,0 +1,88 @@
+package gochart
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Style is an ANSI terminal text style for highlighting Y values.
+type Style string
+
+// Styles that Highlight can apply.
+const (
+	Bold      Style = "1"
+	Underline Style = "4"
+	Red       Style = "31"
+	Green     Style = "32"
+	Yellow    Style = "33"
+	Blue      Style = "34"
+	Magenta   Style = "35"
+	Cyan      Style = "36"
+)
+
+const (
+	kStyleReset = "\x1b[0m"
+)
+
+// Highlight shows each Y value in the given styles when pred returns true
+// for that X and Y value. If several Highlight options match the same Y
+// value, their styles combine. Padding and widths are based only on the
+// visible characters of values. Styles are written only when writing the
+// chart to a terminal unless AlwaysStyle is also given.
+func Highlight(pred func(x, y interface{}) bool, styles ...Style) Option {
+	return optionFunc(func(s *settingsType) {
+		s.highlights = append(
+			s.highlights, highlightType{pred: pred, styles: styles})
+	})
+}
+
+// AlwaysStyle writes the styles from Highlight even when the chart is not
+// being written to a terminal, for instance when piping it to less -R.
+func AlwaysStyle() Option {
+	return optionFunc(func(s *settingsType) {
+		s.alwaysStyle = true
+	})
+}
+
+type highlightType struct {
+	pred   func(x, y interface{}) bool
+	styles []Style
+}
+
+// styleOf returns the ANSI escape sequence that starts the styles for
+// the given X and Y value or the empty string if there are none.
+func (s *settingsType) styleOf(x, y interface{}) string {
+	var codes []string
+	for _, h := range s.highlights {
+		if h.pred(x, y) {
+			for _, style := range h.styles {
+				codes = append(codes, string(style))
+			}
+		}
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// styleCell pads text to width and surrounds it with style. If style is
+// empty, styleCell returns text unchanged.
+func styleCell(text string, width int, style string) string {
+	if style == "" {
+		return text
+	}
+	return style + fmt.Sprintf("%*s", width, text) + kStyleReset
+}
+
+// isTerminal returns true if w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,112 @@
+package gochart
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeStep is the step between consecutive values of a Times sequence.
+// A TimeStep is either a calendar step such as 1 month or a fixed
+// duration such as 90 minutes.
+type TimeStep struct {
+	years    int
+	months   int
+	days     int
+	duration time.Duration
+}
+
+// Every returns a TimeStep of fixed duration d.
+func Every(d time.Duration) TimeStep {
+	return TimeStep{duration: d}
+}
+
+// EveryDate returns a calendar TimeStep that adds the given number of
+// years, months, and days as time.Time.AddDate does.
+func EveryDate(years, months, days int) TimeStep {
+	return TimeStep{years: years, months: months, days: days}
+}
+
+// ParseTimeStep parses a TimeStep such as "1 month", "2 weeks", "1 year",
+// or "3 days". The units are year, month, week, and day, optionally in
+// plural. If s is not a calendar step, ParseTimeStep parses s as a fixed
+// duration with time.ParseDuration, e.g. "90m" or "1h30m".
+func ParseTimeStep(s string) (TimeStep, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return TimeStep{}, fmt.Errorf("gochart: invalid time step %q", s)
+		}
+		return Every(d), nil
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return TimeStep{}, fmt.Errorf("gochart: invalid time step %q", s)
+	}
+	switch strings.TrimSuffix(strings.ToLower(fields[1]), "s") {
+	case "year":
+		return EveryDate(n, 0, 0), nil
+	case "month":
+		return EveryDate(0, n, 0), nil
+	case "week":
+		return EveryDate(0, 0, 7*n), nil
+	case "day":
+		return EveryDate(0, 0, n), nil
+	default:
+		return TimeStep{}, fmt.Errorf("gochart: invalid time step %q", s)
+	}
+}
+
+// Times is a sequence of time.Time X values.
+// Note that Times implements the Values interface.
+type Times struct {
+	start time.Time
+	step  TimeStep
+	count int
+}
+
+// NewTimes returns a sequence of count times starting at start and
+// advancing by step. Each time is computed directly from start so that
+// calendar steps do not drift; however, dates that do not exist are
+// normalized as in time.Time.AddDate, so one month after January 31 is
+// March 2 or 3.
+func NewTimes(start time.Time, step TimeStep, count int) *Times {
+	return &Times{start: start, step: step, count: count}
+}
+
+// Apply applies fn to each of these X values and returns the resulting
+// Y values.
+func (t *Times) Apply(fn func(time.Time) float64) Values {
+	result := make(valueSlice, t.count)
+	for i := 0; i < t.count; i++ {
+		result[i] = fn(t.value(i))
+	}
+	return result
+}
+
+func (t *Times) Value(idx int) interface{} {
+	if idx < 0 || idx >= t.count {
+		panic(kIdxOutOfRange)
+	}
+	return t.value(idx)
+}
+
+func (t *Times) Len() int {
+	return t.count
+}
+
+func (t *Times) value(idx int) time.Time {
+	result := t.start.AddDate(
+		idx*t.step.years, idx*t.step.months, idx*t.step.days)
+	return result.Add(time.Duration(idx) * t.step.duration)
+}
+
+// TimeLayout formats time.Time values with layout as in time.Time.Format.
+// TimeLayout overrides XFormat and YFormat for time.Time values.
+func TimeLayout(layout string) Option {
+	return optionFunc(func(s *settingsType) {
+		s.timeLayout = layout
+	})
+}
@@ -0,0 +1,96 @@
+package gochart
+
+// The functions in this file derive new Values from existing ones. The
+// derived Values have the same length as the originals so that they line
+// up with the same X values in NewChart. Unless stated otherwise, they
+// work with int64, float64, *big.Int, and *big.Rat values and panic on
+// other types. Combining int64 values yields int64 values except where an
+// int64 would overflow, in which case the result is a *big.Int.
+
+// Diff returns the first differences of vs. The first value is vs[0] as
+// if vs were preceded by 0, and each later value is vs[i] - vs[i-1].
+// Diff undoes CumSum.
+func Diff(vs Values) Values {
+	result := make(valueSlice, vs.Len())
+	var prev interface{} = int64(0)
+	for i := range result {
+		current := vs.Value(i)
+		result[i] = subValues(current, prev)
+		prev = current
+	}
+	return result
+}
+
+// Ratio returns the ratios of consecutive values of vs. The first value is
+// vs[0] as if vs were preceded by 1, and each later value is
+// vs[i] / vs[i-1]. Ratios of float64 values are float64; other ratios are
+// exact *big.Rat values. An exact ratio where vs[i-1] is 0 is nil.
+// Ratio undoes CumProd.
+func Ratio(vs Values) Values {
+	result := make(valueSlice, vs.Len())
+	var prev interface{} = int64(1)
+	for i := range result {
+		current := vs.Value(i)
+		result[i] = divValues(current, prev)
+		prev = current
+	}
+	return result
+}
+
+// CumSum returns the partial sums of vs: vs[0], vs[0]+vs[1], ...
+func CumSum(vs Values) Values {
+	return accumulate(vs, addValues)
+}
+
+// CumProd returns the partial products of vs: vs[0], vs[0]*vs[1], ...
+func CumProd(vs Values) Values {
+	return accumulate(vs, mulValues)
+}
+
+// CumMax returns the running maximum of vs.
+func CumMax(vs Values) Values {
+	return accumulate(vs, func(x, y interface{}) interface{} {
+		if compareValues(y, x) > 0 {
+			return y
+		}
+		return x
+	})
+}
+
+// Map applies f to each value of vs and returns the results. f may accept
+// and return values of any type.
+func Map(vs Values, f func(v interface{}) interface{}) Values {
+	result := make(valueSlice, vs.Len())
+	for i := range result {
+		result[i] = f(vs.Value(i))
+	}
+	return result
+}
+
+// Zip applies f to corresponding values of xs and ys and returns the
+// results. f may accept and return values of any type. xs and ys must be
+// the same length or else Zip panics.
+func Zip(xs, ys Values, f func(x, y interface{}) interface{}) Values {
+	if xs.Len() != ys.Len() {
+		panic("xs and ys must have same length")
+	}
+	result := make(valueSlice, xs.Len())
+	for i := range result {
+		result[i] = f(xs.Value(i), ys.Value(i))
+	}
+	return result
+}
+
+func accumulate(vs Values, op func(x, y interface{}) interface{}) Values {
+	result := make(valueSlice, vs.Len())
+	for i := range result {
+		if i == 0 {
+			result[i] = vs.Value(0)
+			// Panics if vs[0] is not a number.
+			kindOf(result[i])
+		} else {
+			result[i] = op(result[i-1], vs.Value(i))
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package gochart_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/keep94/gochart"
+)
+
+func TestDiff(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 5)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	assertValuesEqual(
+		t,
+		gochart.Diff(ys),
+		int64(1), int64(3), int64(5), int64(7), int64(9))
+	assertValuesEqual(
+		t,
+		gochart.Diff(gochart.Diff(ys)),
+		int64(1), int64(2), int64(2), int64(2), int64(2))
+	floats := gochart.NewFloats(0.5, 0.25, 3)
+	assertValuesEqual(t, gochart.Diff(floats), 0.5, 0.25, 0.25)
+}
+
+func TestDiffUndoesCumSum(t *testing.T) {
+	ys := gochart.NewInts(math.MaxInt64-1, -3, 3)
+	sums := gochart.CumSum(ys)
+	if _, ok := sums.Value(1).(*big.Int); !ok {
+		t.Error("Expected overflowing sum to be a *big.Int")
+	}
+	assertEqual(t, int64(math.MaxInt64-7), ys.Value(2))
+	assertSprintValuesEqual(
+		t,
+		gochart.Diff(sums),
+		"9223372036854775806",
+		"9223372036854775803",
+		"9223372036854775800")
+}
+
+func TestRatio(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 4)
+	ys := xs.Apply(func(x int64) int64 { return x * x })
+	ratios := gochart.Ratio(ys)
+	assertSprintValuesEqual(t, ratios, "1/1", "4/1", "9/4", "16/9")
+	assertSprintValuesEqual(
+		t, gochart.CumProd(ratios), "1/1", "4/1", "9/1", "16/1")
+	zeros := gochart.NewInts(0, 1, 2)
+	assertEqual(t, nil, gochart.Ratio(zeros).Value(1))
+	floats := gochart.NewFloats(1.0, 1.0, 3)
+	assertValuesEqual(t, gochart.Ratio(floats), 1.0, 2.0, 1.5)
+}
+
+func TestCumProd(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 25)
+	factorials := gochart.CumProd(xs)
+	assertEqual(t, int64(120), factorials.Value(4))
+	expected := new(big.Int).MulRange(1, 25)
+	if factorials.Value(24).(*big.Int).Cmp(expected) != 0 {
+		t.Errorf("Expected %v, got %v", expected, factorials.Value(24))
+	}
+}
+
+func TestCumMax(t *testing.T) {
+	ys := gochart.NewInts(1, 1, 6).ApplySlice([]int64{3, 1, 4, 1, 5, 9})
+	assertValuesEqual(
+		t,
+		gochart.CumMax(ys),
+		int64(3), int64(3), int64(4), int64(4), int64(5), int64(9))
+}
+
+func TestMapZip(t *testing.T) {
+	xs := gochart.NewInts(1, 1, 3)
+	doubled := gochart.Map(xs, func(v interface{}) interface{} {
+		return 2 * v.(int64)
+	})
+	assertValuesEqual(t, doubled, int64(2), int64(4), int64(6))
+	sums := gochart.Zip(xs, doubled, func(x, y interface{}) interface{} {
+		return x.(int64) + y.(int64)
+	})
+	assertValuesEqual(t, sums, int64(3), int64(6), int64(9))
+	assertPanic(t, func() {
+		gochart.Zip(xs, gochart.NewInts(1, 1, 2), nil)
+	})
+}
+
+func TestCumSumPanic(t *testing.T) {
+	xs := gochart.NewFloats(1.0, 1.0, 1)
+	ys := xs.ApplyComplex(func(x float64) complex128 { return complex(x, x) })
+	assertPanic(t, func() { gochart.CumSum(ys) })
+}
+
+func assertSprintValuesEqual(
+	t *testing.T, ys gochart.Values, expectedValues ...string) {
+	t.Helper()
+	if ys.Len() != len(expectedValues) {
+		t.Fatalf("Expected %v values, but got %v", len(expectedValues), ys.Len())
+	}
+	for i := 0; i < ys.Len(); i++ {
+		if actual := fmt.Sprint(ys.Value(i)); actual != expectedValues[i] {
+			t.Errorf("Expected %v, got %v", expectedValues[i], actual)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package gochart
+
+import (
+	"strings"
+)
+
+// Transpose lays the chart out sideways with X values across one line and
+// the corresponding Y values on the line below. Each X value and its Y
+// value share a column just wide enough for both. When the cells do not fit
+// within maxWidth characters, the chart wraps into several bands, each
+// with its own X and Y lines, separated by blank lines. A footer, if any,
+// goes in a band of its own. Transposed charts ignore NumRows, NumCols,
+// RuleEvery, and RulesAt. maxWidth <= 0 means no transposing, which is the
+// default.
+func Transpose(maxWidth int) Option {
+	return optionFunc(func(s *settingsType) {
+		s.transposeWidth = maxWidth
+	})
+}
+
+// writeTransposed writes this chart to cw in the transposed layout.
+func (c *Chart) writeTransposed(cw *chartWriter) {
+	bands := c.bands(c.xyValues)
+	if len(c.footer) > 0 {
+		bands = append(bands, c.bands(c.footer)...)
+	}
+	if len(bands) == 0 {
+		bands = append(bands, bandType{})
+	}
+	for i, band := range bands {
+		if i > 0 {
+			cw.println()
+		}
+		band.write(cw, len(c.widths), c.wrapWidth)
+	}
+}
+
+// bands divides xyValues into bands no wider than the maximum width.
+func (c *Chart) bands(xyValues xyValuesType) []bandType {
+	var result []bandType
+	var current bandType
+	lineWidth := 1
+	for _, xyValue := range xyValues {
+		var width int
+		for i := range c.widths {
+			width = maxInt(width, cellWidth(xyValue.cell(i)))
+		}
+		if c.wrapWidth > 0 {
+			width = minInt(width, c.wrapWidth)
+		}
+		if len(current.values) > 0 && lineWidth+width+1 > c.transposeWidth {
+			result = append(result, current)
+			current = bandType{}
+			lineWidth = 1
+		}
+		current.values = append(current.values, xyValue)
+		current.widths = append(current.widths, width)
+		lineWidth += width + 1
+	}
+	if len(current.values) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// bandType is one band of a transposed chart.
+type bandType struct {
+	values xyValuesType
+	widths []int
+}
+
+// write writes this band to cw. numCells is the number of cells, X and Y,
+// for each X value.
+func (b bandType) write(cw *chartWriter, numCells, wrapWidth int) {
+	var border strings.Builder
+	for _, width := range b.widths {
+		border.WriteString("+")
+		border.WriteString(strings.Repeat("-", width))
+	}
+	border.WriteString("+")
+	cw.println(border.String())
+	for i := 0; i < numCells; i++ {
+		b.writeLine(cw, i, wrapWidth)
+	}
+	cw.println(border.String())
+}
+
+// writeLine writes the line of this band with the cellIdx cells to cw,
+// spilling onto more lines if values are wrapped. A cellIdx of 0 means the
+// X line; 1 means the first Y line etc.
+func (b bandType) writeLine(cw *chartWriter, cellIdx, wrapWidth int) {
+	lines := make([][]string, len(b.values))
+	numLines := 1
+	for i := range b.values {
+		lines[i] = splitCell(b.values[i].cell(cellIdx), wrapWidth)
+		numLines = maxInt(numLines, len(lines[i]))
+	}
+	for k := 0; k < numLines; k++ {
+		for i, width := range b.widths {
+			line := lineAt(lines[i], k)
+			if cellIdx > 0 {
+				line = cw.style(line, width, b.values[i].style)
+			}
+			cw.printf("|%*s", width, line)
+		}
+		cw.println("|")
+	}
+}